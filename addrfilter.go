@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// addressPattern matches resource addresses by glob or regular expression.
+// Patterns wrapped in slashes (/.../) or prefixed with "re:" are regular
+// expressions; anything else is a glob where * matches any characters.
+type addressPattern struct {
+	raw  string
+	glob string
+	re   *regexp.Regexp
+}
+
+// compileAddressPattern parses a single include or exclude pattern
+func compileAddressPattern(raw string) (addressPattern, error) {
+	p := addressPattern{raw: raw}
+	expr := ""
+	switch {
+	case strings.HasPrefix(raw, "re:"):
+		expr = strings.TrimPrefix(raw, "re:")
+	case len(raw) > 2 && strings.HasPrefix(raw, "/") && strings.HasSuffix(raw, "/"):
+		expr = raw[1 : len(raw)-1]
+	default:
+		if _, err := path.Match(raw, ""); err != nil {
+			return p, fmt.Errorf("invalid glob %q: %w", raw, err)
+		}
+		p.glob = raw
+		return p, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return p, fmt.Errorf("invalid regex %q: %w", raw, err)
+	}
+	p.re = re
+	return p, nil
+}
+
+// moduleCallPattern matches one "module.<name>[key]" segment of an address
+var moduleCallPattern = regexp.MustCompile(`^module\.[^.\[]+(\[[^\]]*\])?\.`)
+
+// modulePaths returns the module paths containing an address, from outermost
+// to innermost, e.g. "module.a.module.b.aws_x.y" gives "module.a" and
+// "module.a.module.b".
+func modulePaths(address string) []string {
+	var paths []string
+	rest := address
+	prefix := ""
+	for {
+		loc := moduleCallPattern.FindStringIndex(rest)
+		if loc == nil {
+			return paths
+		}
+		prefix += rest[:loc[1]]
+		paths = append(paths, strings.TrimSuffix(prefix, "."))
+		rest = rest[loc[1]:]
+	}
+}
+
+// matches reports whether the pattern matches the address or one of its
+// module paths. An exact match always counts, so instance keys like
+// module.app["web"] need not be escaped.
+func (p addressPattern) matches(address string) bool {
+	candidates := append([]string{address}, modulePaths(address)...)
+	for _, c := range candidates {
+		if c == p.raw {
+			return true
+		}
+		if p.re != nil {
+			if p.re.MatchString(c) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(p.glob, c); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// addressFilter keeps resources matching any include pattern and no exclude pattern
+type addressFilter struct {
+	include []addressPattern
+	exclude []addressPattern
+}
+
+// newAddressFilter compiles include and exclude patterns
+func newAddressFilter(include, exclude []string) (addressFilter, error) {
+	var f addressFilter
+	for _, raw := range include {
+		p, err := compileAddressPattern(raw)
+		if err != nil {
+			return f, err
+		}
+		f.include = append(f.include, p)
+	}
+	for _, raw := range exclude {
+		p, err := compileAddressPattern(raw)
+		if err != nil {
+			return f, err
+		}
+		f.exclude = append(f.exclude, p)
+	}
+	return f, nil
+}
+
+// parseAddressFilter parses the filter prompt: space-separated patterns, with
+// a leading "!" marking an exclude.
+func parseAddressFilter(input string) (addressFilter, error) {
+	var include, exclude []string
+	for _, field := range strings.Fields(input) {
+		if strings.HasPrefix(field, "!") {
+			exclude = append(exclude, field[1:])
+		} else {
+			include = append(include, field)
+		}
+	}
+	return newAddressFilter(include, exclude)
+}
+
+// empty reports whether the filter has no patterns
+func (f addressFilter) empty() bool {
+	return len(f.include) == 0 && len(f.exclude) == 0
+}
+
+// allows reports whether an address passes the filter
+func (f addressFilter) allows(address string) bool {
+	for _, p := range f.exclude {
+		if p.matches(address) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, p := range f.include {
+		if p.matches(address) {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats the filter as it would be typed in the filter prompt
+func (f addressFilter) String() string {
+	var parts []string
+	for _, p := range f.include {
+		parts = append(parts, p.raw)
+	}
+	for _, p := range f.exclude {
+		parts = append(parts, "!"+p.raw)
+	}
+	return strings.Join(parts, " ")
+}
+
+// startAddressFilter opens the address filter prompt, pre-filled with the current filter
+func (m *Model) startAddressFilter() {
+	m.editingFilter = true
+	m.filterInput = m.addrFilter.String()
+	m.filterErr = ""
+}
+
+// handleAddressFilterInput processes keyboard input in the address filter prompt
+func (m Model) handleAddressFilterInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.editingFilter = false
+		m.filterErr = ""
+
+	case tea.KeyEnter:
+		f, err := parseAddressFilter(m.filterInput)
+		if err != nil {
+			m.filterErr = err.Error()
+			return m, nil
+		}
+		m.editingFilter = false
+		m.filterErr = ""
+		m.addrFilter = f
+		m.rebuildLines()
+		m.clampCursor()
+		m.clampOffset()
+
+	case tea.KeyBackspace, tea.KeyDelete:
+		if r := []rune(m.filterInput); len(r) > 0 {
+			m.filterInput = string(r[:len(r)-1])
+		}
+
+	case tea.KeyCtrlU:
+		m.filterInput = ""
+
+	case tea.KeyRunes:
+		m.filterInput += string(msg.Runes)
+
+	case tea.KeySpace:
+		m.filterInput += " "
+	}
+	return m, nil
+}
+
+// renderAddressFilterBar renders the address filter prompt
+func (m Model) renderAddressFilterBar() string {
+	t := m.theme()
+	bar := t.Prompt.Render("filter: ") + t.Default.Render(m.filterInput) + t.Dim.Render("█")
+	if m.filterErr != "" {
+		return bar + "  " + t.Error.Render(m.filterErr)
+	}
+	return bar + "  " + t.Dim.Render("glob or /regex/, !pattern excludes, Enter:apply  Esc:cancel")
+}
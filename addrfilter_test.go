@@ -0,0 +1,185 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// addrFilterResources mixes root, module and nested module addresses
+var addrFilterResources = []ResourceChange{
+	planResource("aws_vpc.main", "create"),
+	planResource("module.db.aws_db_instance.main", "update"),
+	planResource("module.db.aws_iam_role.monitoring", "create"),
+	planResource(`module.app["web"].module.db.aws_db_instance.replica`, "destroy"),
+	planResource("aws_iam_policy.deploy", "create"),
+}
+
+func TestModulePaths(t *testing.T) {
+	got := modulePaths(`module.app["web"].module.db.aws_db_instance.replica`)
+	want := []string{`module.app["web"]`, `module.app["web"].module.db`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("modulePaths = %v, want %v", got, want)
+	}
+	if got := modulePaths("aws_vpc.main"); got != nil {
+		t.Errorf("expected no module paths for a root resource, got %v", got)
+	}
+}
+
+func TestAddressPatternMatching(t *testing.T) {
+	cases := []struct {
+		pattern string
+		address string
+		want    bool
+	}{
+		{"module.db.*", "module.db.aws_db_instance.main", true},
+		{"module.db.*", "aws_vpc.main", false},
+		{"*aws_iam_*", "aws_iam_policy.deploy", true},
+		{"*aws_iam_*", "module.db.aws_iam_role.monitoring", true},
+		{"module.db", "module.db.aws_db_instance.main", true},
+		{`module.app["web"]`, `module.app["web"].module.db.aws_db_instance.replica`, true},
+		{"/^aws_(vpc|subnet)\\./", "aws_vpc.main", true},
+		{"re:replica$", "module.app.aws_db_instance.replica", true},
+		{"/^aws_(vpc|subnet)\\./", "module.db.aws_vpc.main", false},
+	}
+	for _, c := range cases {
+		p, err := compileAddressPattern(c.pattern)
+		if err != nil {
+			t.Fatalf("compileAddressPattern(%q): %v", c.pattern, err)
+		}
+		if got := p.matches(c.address); got != c.want {
+			t.Errorf("%q matches %q = %v, want %v", c.pattern, c.address, got, c.want)
+		}
+	}
+}
+
+func TestAddressPatternErrors(t *testing.T) {
+	if _, err := compileAddressPattern("/(unclosed/"); err == nil {
+		t.Error("expected an invalid regex to be rejected")
+	}
+	if _, err := compileAddressPattern("aws_[vpc"); err == nil {
+		t.Error("expected an invalid glob to be rejected")
+	}
+}
+
+func TestAddressFilterIncludeExclude(t *testing.T) {
+	f, err := parseAddressFilter("module.db.* !*aws_iam_*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.allows("module.db.aws_db_instance.main") {
+		t.Error("expected included address to be allowed")
+	}
+	if f.allows("module.db.aws_iam_role.monitoring") {
+		t.Error("expected exclude to win over include")
+	}
+	if f.allows("aws_vpc.main") {
+		t.Error("expected address outside the include to be hidden")
+	}
+	if got := f.String(); got != "module.db.* !*aws_iam_*" {
+		t.Errorf("String() = %q", got)
+	}
+
+	var empty addressFilter
+	if !empty.allows("anything") {
+		t.Error("expected an empty filter to allow everything")
+	}
+}
+
+func TestAddressFilterPrompt(t *testing.T) {
+	m := planModel(120, 40, addrFilterResources...)
+
+	m = typeKeys(m, "F")
+	if !m.editingFilter {
+		t.Fatal("expected F to open the address filter prompt")
+	}
+	m = typeKeys(m, "module.db.*", "enter")
+	if m.editingFilter {
+		t.Error("expected Enter to close the prompt")
+	}
+	want := []string{"module.db.aws_db_instance.main", "module.db.aws_iam_role.monitoring"}
+	if got := visibleAddresses(m); !reflect.DeepEqual(got, want) {
+		t.Errorf("visible = %v, want %v", got, want)
+	}
+	if header := m.renderHeader(); !strings.Contains(header, "filter: module.db.*") {
+		t.Errorf("expected header to show the address filter, got %q", header)
+	}
+
+	// Reopening the prompt pre-fills the current filter; clearing it shows everything
+	m = typeKeys(m, "F")
+	if m.filterInput != "module.db.*" {
+		t.Errorf("expected prompt pre-filled with current filter, got %q", m.filterInput)
+	}
+	m = typeKeys(m, "ctrl+u", "enter")
+	if got := visibleAddresses(m); len(got) != 5 {
+		t.Errorf("expected cleared filter to show all resources, got %v", got)
+	}
+}
+
+func TestAddressFilterPromptRejectsInvalidPattern(t *testing.T) {
+	m := planModel(120, 40, addrFilterResources...)
+	m = typeKeys(m, "F", "/(/", "enter")
+	if !m.editingFilter || m.filterErr == "" {
+		t.Error("expected an invalid pattern to keep the prompt open with an error")
+	}
+	m = typeKeys(m, "esc")
+	if m.editingFilter || !m.addrFilter.empty() {
+		t.Error("expected Esc to cancel without changing the filter")
+	}
+}
+
+func TestAddressFilterExpandCollapseVisibleOnly(t *testing.T) {
+	m := planModel(120, 40, addrFilterResources...)
+	m.addrFilter, _ = newAddressFilter([]string{"module.db.*"}, nil)
+	m.rebuildLines()
+
+	m = typeKeys(m, "e")
+	for _, rc := range m.resources {
+		visible := strings.HasPrefix(rc.Address, "module.db.")
+		if rc.Expanded != visible {
+			t.Errorf("%s expanded = %v, want %v", rc.Address, rc.Expanded, visible)
+		}
+	}
+}
+
+func TestAddressFilterFooterCounts(t *testing.T) {
+	m := planModel(120, 40, addrFilterResources...)
+	m.addrFilter, _ = newAddressFilter(nil, []string{"*aws_iam_*"})
+	m.rebuildLines()
+
+	footer := stripANSI(m.renderFooter())
+	if !strings.Contains(footer, "showing 3 of 5") {
+		t.Errorf("expected filtered count in footer, got %q", footer)
+	}
+	if !strings.Contains(footer, "+1 create") {
+		t.Errorf("expected summary to count only visible resources, got %q", footer)
+	}
+}
+
+func TestParseArgsAddressFilters(t *testing.T) {
+	opts, cmd, err := parseArgs([]string{"--include", "module.db.*", "--exclude=*aws_iam_*", "terraform", "plan", "--include"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cmd, []string{"terraform", "plan", "--include"}) {
+		t.Errorf("command = %v", cmd)
+	}
+	if got := opts.addrFilter.String(); got != "module.db.* !*aws_iam_*" {
+		t.Errorf("filter = %q", got)
+	}
+
+	_, cmd, err = parseArgs([]string{"--", "--weird-binary"})
+	if err != nil || !reflect.DeepEqual(cmd, []string{"--weird-binary"}) {
+		t.Errorf("expected -- to end options, got %v, %v", cmd, err)
+	}
+
+	if _, _, err := parseArgs([]string{"--include"}); err == nil {
+		t.Error("expected a missing pattern to be an error")
+	}
+	if _, _, err := parseArgs([]string{"--bogus", "terraform"}); err == nil {
+		t.Error("expected an unknown option to be an error")
+	}
+	if _, _, err := parseArgs([]string{"--include", "/(/"}); err == nil {
+		t.Error("expected an invalid pattern to be an error")
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Error categories assigned by the classifier
+const (
+	CategoryAuth       = "auth"
+	CategoryQuota      = "quota"
+	CategoryThrottling = "throttling"
+	CategoryNotFound   = "not-found"
+	CategoryValidation = "validation"
+)
+
+var (
+	// statusPattern extracts HTTP statuses as printed by the AWS, Azure and GCP SDKs:
+	// "status code: 403", "StatusCode=404", "StatusCode: 429", "googleapi: Error 400".
+	statusPattern = regexp.MustCompile(`(?i)(?:status\s*code\s*[:=]?\s*|googleapi: Error\s+|HTTP\s+)(\d{3})\b`)
+	// codeTokenPattern splits text into candidate provider error codes
+	codeTokenPattern = regexp.MustCompile(`[A-Za-z][A-Za-z0-9_.]*`)
+)
+
+// ClassifierRule maps provider error codes, HTTP statuses or a pattern to a category.
+// Codes match a word in the diagnostic exactly or as a suffix, so "NotFound"
+// also matches "InvalidInstanceID.NotFound" and "ResourceGroupNotFound".
+type ClassifierRule struct {
+	Category string   `json:"category"`
+	Codes    []string `json:"codes,omitempty"`
+	Statuses []int    `json:"statuses,omitempty"`
+	Pattern  string   `json:"pattern,omitempty"`
+	Hint     string   `json:"hint,omitempty"`
+}
+
+// builtinRules are ordered so that specific codes win over broad ones
+// (e.g. RequestLimitExceeded is throttling, not quota). Their patterns use
+// provider API wording so Terraform's own errors, such as "Module not found",
+// are left unclassified.
+var builtinRules = []ClassifierRule{
+	{
+		Category: CategoryThrottling,
+		Codes:    []string{"RequestLimitExceeded", "Throttling", "ThrottlingException", "TooManyRequests", "rateLimitExceeded", "userRateLimitExceeded", "SlowDown"},
+		Statuses: []int{429},
+		Pattern:  `(?i)rate limit|throttl`,
+		Hint:     "The provider API is rate limiting requests. Retry later, lower -parallelism, or raise the provider's max_retries.",
+	},
+	{
+		Category: CategoryQuota,
+		Codes:    []string{"QuotaExceeded", "quotaExceeded", "LimitExceeded", "ServiceQuotaExceededException", "OperationNotAllowed"},
+		Pattern:  `(?i)quota .*exceeded|quota exceeded|exceeds? .*quota`,
+		Hint:     "An account or project quota has been reached. Request a quota increase from the provider console or free up existing resources.",
+	},
+	{
+		Category: CategoryAuth,
+		Codes:    []string{"AccessDenied", "AccessDeniedException", "UnauthorizedOperation", "AuthorizationFailed", "AuthFailure", "InvalidClientTokenId", "ExpiredToken", "ExpiredTokenException", "SignatureDoesNotMatch", "PERMISSION_DENIED", "UNAUTHENTICATED", "InvalidAuthenticationToken"},
+		Statuses: []int{401, 403},
+		Pattern:  `(?i)permission denied|not authorized to|unauthorized|no valid credential|invalid credentials|credentials (?:have |has )?expired`,
+		Hint:     "The credentials in use lack permission for this action or have expired. Check the active profile/identity and its IAM policy or role assignments.",
+	},
+	{
+		Category: CategoryNotFound,
+		Codes:    []string{"NotFound", "NoSuchBucket", "NoSuchEntity", "NoSuchKey", "ResourceNotFoundException"},
+		Statuses: []int{404},
+		Pattern:  `(?i)\b(?:was|were) not found\b|could not be found|\b(?:resource|object|entity) does not exist|\bno such (?:bucket|key|entity)\b`,
+		Hint:     "A referenced object does not exist. It may have been deleted outside Terraform, or the region/project/subscription is wrong. Consider terraform state rm or refresh.",
+	},
+	{
+		Category: CategoryValidation,
+		Codes:    []string{"InvalidParameterValue", "InvalidParameterCombination", "InvalidParameter", "ValidationException", "ValidationError", "InvalidInstanceType", "MalformedPolicyDocument", "InvalidRequest", "BadRequest", "INVALID_ARGUMENT"},
+		Statuses: []int{400, 422},
+		Pattern:  `(?i)\binvalid (?:parameter|request)\b|invalid value for (?:parameter|field|property)|\bvalidation (?:error|failed|exception)\b`,
+		Hint:     "The provider rejected an argument value. Check the attribute against the provider documentation for allowed values and formats.",
+	},
+}
+
+// compiledRule is a ClassifierRule with its pattern compiled
+type compiledRule struct {
+	ClassifierRule
+	re *regexp.Regexp
+}
+
+// Classifier assigns categories and remediation hints to diagnostics
+type Classifier struct {
+	rules []compiledRule
+}
+
+// newClassifier builds a classifier from user rules followed by the built-in rules
+func newClassifier(userRules []ClassifierRule) (*Classifier, error) {
+	c := &Classifier{}
+	for _, rule := range append(append([]ClassifierRule{}, userRules...), builtinRules...) {
+		cr := compiledRule{ClassifierRule: rule}
+		if rule.Pattern != "" {
+			re, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("classifier rule %q: %w", rule.Category, err)
+			}
+			cr.re = re
+		}
+		c.rules = append(c.rules, cr)
+	}
+	return c, nil
+}
+
+// Classify returns the first rule matching the diagnostic. Error codes and
+// patterns are checked across all rules before HTTP statuses, since a status
+// like 403 is shared by auth and quota failures.
+func (c *Classifier) Classify(diag Diagnostic) (ClassifierRule, bool) {
+	if diag.Severity != "error" {
+		return ClassifierRule{}, false
+	}
+
+	var text strings.Builder
+	text.WriteString(diag.Summary)
+	for _, d := range diag.Detail {
+		text.WriteString("\n")
+		text.WriteString(stripANSI(d.Content))
+	}
+	body := text.String()
+
+	tokens := codeTokenPattern.FindAllString(body, -1)
+	for _, rule := range c.rules {
+		if rule.matchesCode(tokens) || (rule.re != nil && rule.re.MatchString(body)) {
+			return rule.ClassifierRule, true
+		}
+	}
+
+	for _, match := range statusPattern.FindAllStringSubmatch(body, -1) {
+		status, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		for _, rule := range c.rules {
+			for _, s := range rule.Statuses {
+				if s == status {
+					return rule.ClassifierRule, true
+				}
+			}
+		}
+	}
+	return ClassifierRule{}, false
+}
+
+// matchesCode reports whether any token equals or ends with one of the rule's codes
+func (r compiledRule) matchesCode(tokens []string) bool {
+	for _, code := range r.Codes {
+		for _, tok := range tokens {
+			if strings.HasSuffix(tok, code) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// classifier returns the model's classifier, falling back to the built-in rules
+func (m *Model) classifier() *Classifier {
+	if m.cachedClassifier == nil {
+		m.cachedClassifier, _ = newClassifier(nil)
+	}
+	return m.cachedClassifier
+}
+
+// classifyDiagnostic tags a diagnostic with its category and remediation hint
+func (m *Model) classifyDiagnostic(diag *Diagnostic) {
+	if rule, ok := m.classifier().Classify(*diag); ok {
+		diag.Category = rule.Category
+		diag.Hint = rule.Hint
+	}
+}
+
+// toggleHint shows or hides the remediation hint of the diagnostic at lineIdx
+func (m *Model) toggleHint(lineIdx int) {
+	if lineIdx < 0 || lineIdx >= len(m.lines) {
+		return
+	}
+	line := m.lines[lineIdx]
+	switch line.Type {
+	case LineTypeDiagnostic, LineTypeDiagnosticDetail, LineTypeHint:
+	default:
+		return
+	}
+	if line.DiagIdx < 0 || line.DiagIdx >= len(m.diagnostics) || m.diagnostics[line.DiagIdx].Hint == "" {
+		return
+	}
+	m.diagnostics[line.DiagIdx].ShowHint = !m.diagnostics[line.DiagIdx].ShowHint
+	m.rebuildLines()
+	m.clampCursor()
+	m.clampOffset()
+}
+
+// appendHintLines emits the display lines for a diagnostic's remediation hint
+func (m *Model) appendHintLines(diagIdx int) {
+	diag := m.diagnostics[diagIdx]
+	for _, w := range wrapText("Hint: "+diag.Hint, m.width-4, 6) {
+		m.lines = append(m.lines, Line{
+			Type:        LineTypeHint,
+			DiagIdx:     diagIdx,
+			ResourceIdx: -1,
+			AttrIdx:     -1,
+			Content:     w,
+		})
+	}
+}
+
+// renderHintLine renders a remediation hint line
+func (m Model) renderHintLine(line Line, isSelected bool) string {
+	t := m.theme()
+	if isSelected {
+		return t.Selected.Render("►   " + line.Content)
+	}
+	return "    " + t.Import.Render(line.Content)
+}
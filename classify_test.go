@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassifyProviderErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		category string
+	}{
+		{"AWS not found code", "╷\n│ Error: Error launching source instance: InvalidInstanceID.NotFound\n│ \n│ status code: 400\n╵\n", CategoryNotFound},
+		{"AWS unauthorized", "╷\n│ Error: error creating EC2 Instance: UnauthorizedOperation\n│ \n│ status code: 403\n╵\n", CategoryAuth},
+		{"AWS throttling over 503", "╷\n│ Error: RequestLimitExceeded: Request limit exceeded.\n│ \n│ status code: 503\n╵\n", CategoryThrottling},
+		{"AWS invalid instance type", "╷\n│ Error: creating EC2 Instance: InvalidInstanceType: t99.xlarge not supported\n╵\n", CategoryValidation},
+		{"GCP bare 400", "╷\n│ Error: Error creating instance: googleapi: Error 400\n╵\n", CategoryValidation},
+		{"GCP bare 403", "╷\n│ Error: Error creating Bucket: googleapi: Error 403\n╵\n", CategoryAuth},
+		{"GCP quota over 403", "╷\n│ Error: Error creating instance: googleapi: Error 403: Quota exceeded\n╵\n", CategoryQuota},
+		{"Azure resource group", "╷\n│ Error: StatusCode=404 Code=ResourceGroupNotFound\n╵\n", CategoryNotFound},
+		{"Azure authorization", "╷\n│ Error: StatusCode=403 Code=AuthorizationFailed\n╵\n", CategoryAuth},
+		{"HTTP 429", "╷\n│ Error: creating widget: unexpected response, StatusCode: 429\n╵\n", CategoryThrottling},
+		{"Core module not found", "╷\n│ Error: Module not found\n│ \n│   on main.tf line 3:\n│ \n│ The module address \"./modules/db\" could not be resolved.\n╵\n", ""},
+		{"Core input variable", "╷\n│ Error: Invalid value for input variable\n│ \n│   on main.tf line 9:\n│ \n│ The given value is not suitable for var.size.\n╵\n", ""},
+		{"Core argument named credentials", "╷\n│ Error: Unsupported argument\n│ \n│   on main.tf line 4:\n│ \n│ An argument named \"credentials\" is not expected here.\n╵\n", ""},
+		{"Provider object not found", "╷\n│ Error: reading IAM Role (app): NoSuchEntity: The role with name app cannot be found.\n╵\n", CategoryNotFound},
+		{"Provider no credentials", "╷\n│ Error: No valid credential sources found\n╵\n", CategoryAuth},
+		{"Syntax error is unclassified", "╷\n│ Error: Argument or block definition required\n│ \n│   on main.tf line 5:\n╵\n", ""},
+	}
+
+	c, err := newClassifier(nil)
+	if err != nil {
+		t.Fatalf("newClassifier: %v", err)
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Model{streamChan: make(chan StreamMsg, 10)}
+			diags, _, _, _ := collectStreamMsgs(m, tc.input)
+			if len(diags) != 1 {
+				t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+			}
+			rule, _ := c.Classify(*diags[0])
+			if rule.Category != tc.category {
+				t.Errorf("expected category %q, got %q", tc.category, rule.Category)
+			}
+		})
+	}
+}
+
+func TestClassifyWarningsAreIgnored(t *testing.T) {
+	c, _ := newClassifier(nil)
+	if _, ok := c.Classify(Diagnostic{Severity: "warning", Summary: "AccessDenied"}); ok {
+		t.Error("warnings should not be classified")
+	}
+}
+
+func TestClassifyUserRulesTakePrecedence(t *testing.T) {
+	c, err := newClassifier([]ClassifierRule{
+		{Category: "network", Pattern: `(?i)i/o timeout`, Hint: "Check VPN"},
+		{Category: CategoryQuota, Codes: []string{"AccessDenied"}},
+	})
+	if err != nil {
+		t.Fatalf("newClassifier: %v", err)
+	}
+
+	rule, _ := c.Classify(Diagnostic{Severity: "error", Summary: "dial tcp 10.0.0.1:443: i/o timeout"})
+	if rule.Category != "network" || rule.Hint != "Check VPN" {
+		t.Errorf("expected user network rule, got %+v", rule)
+	}
+	rule, _ = c.Classify(Diagnostic{Severity: "error", Summary: "AccessDenied: nope"})
+	if rule.Category != CategoryQuota {
+		t.Errorf("expected user rule to override built-in auth rule, got %q", rule.Category)
+	}
+
+	if _, err := newClassifier([]ClassifierRule{{Category: "bad", Pattern: "("}}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestClassifierBadgeAndHint(t *testing.T) {
+	m := Model{
+		streamChan: make(chan StreamMsg, 10),
+		tab:        TabLog,
+		width:      120,
+	}
+	diag := Diagnostic{Severity: "error", Summary: "StatusCode=403 Code=AuthorizationFailed", Expanded: true}
+	updated, _ := m.Update(StreamMsg{Diagnostic: &diag})
+	m = updated.(Model)
+	m.rebuildLines()
+
+	if m.diagnostics[0].Category != CategoryAuth {
+		t.Fatalf("expected diagnostic to be tagged auth, got %q", m.diagnostics[0].Category)
+	}
+	if header := m.renderDiagnosticLine(m.lines[0], false); !strings.Contains(header, "[auth]") {
+		t.Errorf("expected category badge in header, got %q", header)
+	}
+
+	m.toggleHint(0)
+	foundHint := false
+	for _, l := range m.lines {
+		if l.Type == LineTypeHint {
+			foundHint = true
+		}
+	}
+	if !foundHint {
+		t.Error("expected hint lines after toggling the hint")
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+const usageText = `Usage:
+  terraform plan 2>&1 | terraui [flags]
+  terraui [flags] [--] <command> [args...]
+
+Without a command terraui reads Terraform output from stdin (or --input).
+With one it runs the command in a pseudo-terminal so prompts can be answered.
+
+Flags:
+  --mode MODE         Start in rendering mode dashboard or high-contrast
+  --view VIEW         Start in view plan, diagnostics, log, outputs or summary
+                      and don't switch views automatically
+  --input FILE        Read Terraform output from FILE instead of stdin
+  --export FILE       Write the session's output as plain text to FILE on exit
+                      ("-" for stdout)
+  --config FILE       Read settings from FILE instead of the default config
+  --include PATTERN   Show only matching addresses in the Plan view (repeatable)
+  --exclude PATTERN   Hide matching addresses in the Plan view (repeatable)
+  --no-mouse          Leave the mouse to the terminal (e.g. to select text)
+  --version           Print the version and exit
+  -h, --help          Print this help and exit
+
+Use -- before a command whose name starts with "--".
+
+terraui exits with the command's exit code, 130 if it quit before the command
+finished, or 64 for invalid flags.
+`
+
+// cliOptions holds terraui's own command line options
+type cliOptions struct {
+	addrFilter addressFilter
+	mode       RenderingMode
+	view       Tab    // Starting tab, if viewSet
+	viewSet    bool   // --view was given
+	inputPath  string // Read from this file instead of stdin
+	exportPath string // Write the output here on exit ("-" for stdout)
+	configPath string // Config file, "" for the default
+	noMouse    bool
+	version    bool
+	help       bool
+}
+
+// renderingModeNames maps --mode values to rendering modes
+var renderingModeNames = map[string]RenderingMode{
+	"dashboard":     RenderingModeDashboard,
+	"high-contrast": RenderingModeHighContrast,
+	"highcontrast":  RenderingModeHighContrast,
+}
+
+// parseTab returns the tab named by a --view value
+func parseTab(name string) (Tab, bool) {
+	for i, tabName := range tabNames {
+		if strings.EqualFold(name, tabName) {
+			return Tab(i), true
+		}
+	}
+	return 0, false
+}
+
+// parseArgs splits terraui's options from the command to wrap. Options come
+// first; the first non-option argument (or everything after "--") is the
+// command. Options taking a value accept --name VALUE or --name=VALUE, and
+// address filters may be repeated.
+func parseArgs(args []string) (cliOptions, []string, error) {
+	var opts cliOptions
+	var include, exclude []string
+
+	i := 0
+	for ; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			i++
+			break
+		}
+		if arg == "-h" {
+			opts.help = true
+			continue
+		}
+		if !strings.HasPrefix(arg, "--") {
+			break
+		}
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch name {
+		case "--no-mouse", "--version", "--help":
+			if hasValue {
+				return opts, nil, fmt.Errorf("option %s takes no value", name)
+			}
+			switch name {
+			case "--no-mouse":
+				opts.noMouse = true
+			case "--version":
+				opts.version = true
+			case "--help":
+				opts.help = true
+			}
+			continue
+		case "--include", "--exclude", "--mode", "--view", "--input", "--export", "--config":
+		default:
+			return opts, nil, fmt.Errorf("unknown option %s", name)
+		}
+
+		if !hasValue {
+			if i+1 >= len(args) {
+				return opts, nil, fmt.Errorf("option %s requires a value", name)
+			}
+			i++
+			value = args[i]
+		}
+		switch name {
+		case "--include":
+			include = append(include, value)
+		case "--exclude":
+			exclude = append(exclude, value)
+		case "--mode":
+			mode, ok := renderingModeNames[strings.ToLower(value)]
+			if !ok {
+				return opts, nil, fmt.Errorf("unknown rendering mode %q (want dashboard or high-contrast)", value)
+			}
+			opts.mode = mode
+		case "--view":
+			tab, ok := parseTab(value)
+			if !ok {
+				return opts, nil, fmt.Errorf("unknown view %q (want plan, diagnostics, log, outputs or summary)", value)
+			}
+			opts.view, opts.viewSet = tab, true
+		case "--input":
+			opts.inputPath = value
+		case "--export":
+			opts.exportPath = value
+		case "--config":
+			opts.configPath = value
+		}
+	}
+
+	command := args[i:]
+	if opts.inputPath != "" && len(command) > 0 {
+		return opts, nil, fmt.Errorf("--input can't be combined with a command to run")
+	}
+
+	f, err := newAddressFilter(include, exclude)
+	if err != nil {
+		return opts, nil, err
+	}
+	opts.addrFilter = f
+	return opts, command, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseArgsFlags(t *testing.T) {
+	opts, cmd, err := parseArgs([]string{
+		"--mode", "high-contrast", "--view=summary", "--export", "out.txt",
+		"--config=terraui.json", "--no-mouse", "--", "terraform", "apply", "--help",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.mode != RenderingModeHighContrast || !opts.viewSet || opts.view != TabSummary {
+		t.Errorf("expected high contrast mode and the Summary view, got %+v", opts)
+	}
+	if opts.exportPath != "out.txt" || opts.configPath != "terraui.json" || !opts.noMouse || opts.help {
+		t.Errorf("expected export, config and no-mouse set but not help, got %+v", opts)
+	}
+	if !reflect.DeepEqual(cmd, []string{"terraform", "apply", "--help"}) {
+		t.Errorf("expected everything after -- to be the command, got %v", cmd)
+	}
+}
+
+func TestParseArgsWithoutFlags(t *testing.T) {
+	for _, args := range [][]string{nil, {"terraform", "plan", "-detailed-exitcode"}} {
+		opts, cmd, err := parseArgs(args)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cmd) != len(args) || (len(args) > 0 && !reflect.DeepEqual(cmd, args)) {
+			t.Errorf("expected %v as the command, got %v", args, cmd)
+		}
+		if opts.viewSet || opts.mode != RenderingModeDashboard || opts.noMouse {
+			t.Errorf("expected default options for %v, got %+v", args, opts)
+		}
+	}
+}
+
+func TestParseArgsHelpAndVersion(t *testing.T) {
+	if opts, _, _ := parseArgs([]string{"-h"}); !opts.help {
+		t.Error("expected -h to ask for help")
+	}
+	if opts, _, _ := parseArgs([]string{"--help"}); !opts.help {
+		t.Error("expected --help to ask for help")
+	}
+	if opts, _, _ := parseArgs([]string{"--version"}); !opts.version {
+		t.Error("expected --version to ask for the version")
+	}
+}
+
+func TestParseArgsErrors(t *testing.T) {
+	cases := [][]string{
+		{"--mode", "neon"},
+		{"--view", "graph"},
+		{"--export"},
+		{"--no-mouse=yes"},
+		{"--input", "plan.txt", "terraform", "plan"},
+	}
+	for _, args := range cases {
+		if _, _, err := parseArgs(args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestFixedViewIgnoresAutoSwitch(t *testing.T) {
+	m := Model{width: 80, height: 24, ready: true, tab: TabSummary, fixedView: true}
+	updated, _ := m.Update(StreamMsg{Diagnostic: &Diagnostic{Severity: "error", Summary: "boom"}})
+	m = updated.(Model)
+	if m.tab != TabSummary {
+		t.Errorf("expected --view to keep the Summary tab, got %s", m.tab)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// configEnvVar overrides the default config file location
+const configEnvVar = "TERRAUI_CONFIG"
+
+// Config holds user settings loaded from the terraui config file
+type Config struct {
+	// ClassifierRules are checked before the built-in provider error rules
+	ClassifierRules []ClassifierRule `json:"classifier_rules"`
+
+	// ReviewDir is where review marks are saved (default: reviews/ beside the config file)
+	ReviewDir string `json:"review_dir"`
+
+	// GuardWorkspaces limits the destructive apply guard to matching
+	// workspaces (globs). Empty guards every workspace.
+	GuardWorkspaces []string `json:"guard_workspaces"`
+
+	// SensitiveVariables are variable name globs whose input is masked, in
+	// addition to names that look like secrets
+	SensitiveVariables []string `json:"sensitive_variables"`
+
+	path string // File the config was loaded from, or would be if it existed
+}
+
+// defaultConfigPath returns $TERRAUI_CONFIG, or config.json in the user's
+// config directory (e.g. ~/.config/terraui/config.json).
+func defaultConfigPath() string {
+	if path := os.Getenv(configEnvVar); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "terraui", "config.json")
+}
+
+// loadConfig reads the config file at path. A missing file is not an error
+// and yields an empty Config.
+func loadConfig(path string) (Config, error) {
+	cfg := Config{path: path}
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	cfg, err := loadConfig(filepath.Join(dir, "missing.json"))
+	if err != nil {
+		t.Fatalf("missing config should not be an error: %v", err)
+	}
+	if len(cfg.ClassifierRules) != 0 {
+		t.Error("expected empty config for a missing file")
+	}
+
+	path := filepath.Join(dir, "config.json")
+	data := `{"classifier_rules": [{"category": "network", "pattern": "i/o timeout", "hint": "Check VPN"}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if len(cfg.ClassifierRules) != 1 || cfg.ClassifierRules[0].Category != "network" {
+		t.Errorf("unexpected rules: %+v", cfg.ClassifierRules)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig(path); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// crashBannerLines bounds how many lines of explanatory text may precede the
+// panic in a crash block before it is treated as ordinary output.
+const crashBannerLines = 20
+
+var (
+	// pluginStackPattern matches the line Terraform prints before a plugin's stack trace
+	pluginStackPattern = regexp.MustCompile(`^\s*Stack trace from the (\S+) plugin:`)
+	// pluginCrashedPattern matches the diagnostic summary for a crashed plugin
+	pluginCrashedPattern = regexp.MustCompile(`The (\S+) plugin crashed!`)
+	// crashLogPattern extracts the crash log path from Terraform's crash message
+	crashLogPattern  = regexp.MustCompile(`crash log has been placed at "?([^"\s]+)"?|(\S*crash\.log)\b`)
+	panicPattern     = regexp.MustCompile(`^\s*panic: (.+)`)
+	goroutinePattern = regexp.MustCompile(`^\s*goroutine \d+ \[`)
+	// stackFramePattern matches function frames such as "main.foo(0x1, ...)" and
+	// the tab-indented source positions below them.
+	stackFramePattern = regexp.MustCompile(`^(?:\t|\s{2,}\S+\.go:\d+|[\w./*()\-]+\(.*\)$|created by |\[signal |exit status \d+)`)
+)
+
+// CrashInfo describes a panic stack trace found in the log output
+type CrashInfo struct {
+	Start    int    // Index of the first log line in the crash block
+	End      int    // Index after the last log line in the crash block
+	Message  string // The panic message
+	TopFrame string // First function frame of the panicking goroutine
+	Provider string // Plugin that crashed, empty for a Terraform core crash
+	CrashLog string // Path of the crash log, if Terraform wrote one
+}
+
+// isCrashStart reports whether a log line begins a crash block
+func isCrashStart(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return pluginStackPattern.MatchString(line) ||
+		panicPattern.MatchString(line) ||
+		strings.Contains(trimmed, "TERRAFORM CRASH") ||
+		strings.HasPrefix(trimmed, "Terraform crashed!")
+}
+
+// readCrash reads the crash block starting at logs[i]. The crash has no
+// message if the lines are not a panic. complete is false while the block
+// runs to the end of the logs and may still grow.
+func readCrash(logs []string, i int) (crash CrashInfo, complete bool) {
+	crash = CrashInfo{Start: i}
+	inStack := false
+	j := i
+	for ; j < len(logs); j++ {
+		line := logs[j]
+		if match := pluginStackPattern.FindStringSubmatch(line); match != nil {
+			crash.Provider = match[1]
+			continue
+		}
+		if match := panicPattern.FindStringSubmatch(line); match != nil {
+			if crash.Message == "" {
+				crash.Message = strings.TrimSpace(match[1])
+			}
+			inStack = true
+			continue
+		}
+		if goroutinePattern.MatchString(line) {
+			inStack = true
+			continue
+		}
+		if inStack {
+			if !stackFramePattern.MatchString(line) {
+				break
+			}
+			if crash.TopFrame == "" && !strings.HasPrefix(line, "\t") && !strings.HasPrefix(strings.TrimSpace(line), "[signal") {
+				crash.TopFrame = frameFunction(line)
+			}
+			continue
+		}
+		// Banner text before the panic (e.g. "Terraform crashed! ...")
+		if j-i >= crashBannerLines {
+			// A banner without a panic is not a stack trace worth folding
+			return CrashInfo{Start: i}, true
+		}
+	}
+	crash.End = j
+	return crash, j < len(logs)
+}
+
+// addCrashLog records the crash log path if the log line names one
+func (c *CrashInfo) addCrashLog(line string) {
+	if match := crashLogPattern.FindStringSubmatch(line); match != nil && c.CrashLog == "" {
+		if match[1] != "" {
+			c.CrashLog = match[1]
+		} else {
+			c.CrashLog = match[2]
+		}
+	}
+}
+
+// addCrashProvider records the crashed plugin if a diagnostic summary names one
+func (c *CrashInfo) addCrashProvider(summary string) {
+	if match := pluginCrashedPattern.FindStringSubmatch(summary); match != nil && c.Provider == "" {
+		c.Provider = match[1]
+	}
+}
+
+// crashBefore returns the last finished crash starting at or before log
+// index pos, or nil
+func (m *Model) crashBefore(pos int) *CrashInfo {
+	for i := len(m.crashes) - 1; i >= 0; i-- {
+		if m.crashes[i].Start <= pos {
+			return &m.crashes[i]
+		}
+	}
+	return nil
+}
+
+// scanCrashes finds panic output in log lines that arrived since the last
+// scan and summarises each crash. The lines stay in the logs; the UI folds
+// the range into a single block. A crash log path or crashed plugin printed
+// after a crash, up to the next one, belongs to it.
+func (m *Model) scanCrashes() {
+	// Drop a crash that was still being printed at the last scan
+	if m.crashOpen {
+		m.crashes = m.crashes[:len(m.crashes)-1]
+		m.crashOpen = false
+	}
+
+	var open CrashInfo
+	i := m.crashScan
+	for i < len(m.logs) {
+		if !isCrashStart(m.logs[i]) {
+			i++
+			continue
+		}
+		crash, complete := readCrash(m.logs, i)
+		if !complete {
+			open = crash
+			break
+		}
+		if crash.Message == "" {
+			i++
+			continue
+		}
+		m.crashes = append(m.crashes, crash)
+		i = crash.End
+	}
+
+	// Details are read once the crash they belong to is finished
+	for ; m.crashDetail < i; m.crashDetail++ {
+		if c := m.crashBefore(m.crashDetail); c != nil {
+			c.addCrashLog(m.logs[m.crashDetail])
+		}
+	}
+	for ; m.crashDiags < len(m.diagnostics) && m.diagnostics[m.crashDiags].LogPos <= i; m.crashDiags++ {
+		d := m.diagnostics[m.crashDiags]
+		if c := m.crashBefore(d.LogPos); c != nil {
+			c.addCrashProvider(d.Summary)
+		}
+	}
+	m.crashScan = i
+
+	// A crash at the end of the logs is shown while it is still being printed
+	if open.Message != "" {
+		for _, line := range m.logs[i:] {
+			open.addCrashLog(line)
+		}
+		for _, d := range m.diagnostics[m.crashDiags:] {
+			open.addCrashProvider(d.Summary)
+		}
+		m.crashes = append(m.crashes, open)
+		m.crashOpen = true
+	}
+}
+
+// detectCrashes returns the crashes in a complete log
+func detectCrashes(logs []string, diagnostics []Diagnostic) []CrashInfo {
+	m := Model{logs: logs, diagnostics: diagnostics}
+	m.scanCrashes()
+	return m.crashes
+}
+
+// frameFunction strips the argument list from a stack frame, so
+// "pkg.(*T).read(0xc000a1e000)" becomes "pkg.(*T).read".
+func frameFunction(frame string) string {
+	frame = strings.TrimSpace(frame)
+	if strings.HasSuffix(frame, ")") {
+		if idx := strings.LastIndex(frame, "("); idx > 0 {
+			return frame[:idx]
+		}
+	}
+	return frame
+}
+
+// crashAt returns the index of the crash block starting at log index logIdx
+func (m Model) crashAt(logIdx int) int {
+	for i, c := range m.crashes {
+		if c.Start == logIdx {
+			return i
+		}
+	}
+	return -1
+}
+
+// crashSummary returns the one-line text shown for a folded crash block
+func (c CrashInfo) crashSummary() string {
+	summary := "panic: " + c.Message
+	if c.TopFrame != "" {
+		summary += " at " + c.TopFrame
+	}
+	var extra []string
+	if c.Provider != "" {
+		extra = append(extra, c.Provider)
+	}
+	if c.CrashLog != "" {
+		extra = append(extra, "log: "+c.CrashLog)
+	}
+	if len(extra) > 0 {
+		summary += " (" + strings.Join(extra, ", ") + ")"
+	}
+	return summary
+}
+
+// appendCrashLines emits the folded header for a crash block and, when
+// expanded, the original log lines it covers.
+func (m *Model) appendCrashLines(crashIdx int) {
+	crash := m.crashes[crashIdx]
+	for wIdx, w := range wrapText(crash.crashSummary(), m.width-6, 0) {
+		m.lines = append(m.lines, Line{
+			Type:        LineTypeCrash,
+			ResourceIdx: -1,
+			DiagIdx:     crashIdx,
+			AttrIdx:     wIdx,
+			Content:     w,
+		})
+	}
+	if !m.expandedCrashes[crash.Start] {
+		return
+	}
+	for i := crash.Start; i < crash.End; i++ {
+		for _, w := range wrapText(m.logs[i], m.width-2, 0) {
+			m.lines = append(m.lines, Line{
+				Type:    LineTypeLog,
+				Content: w,
+				AttrIdx: i,
+			})
+		}
+	}
+}
+
+// toggleCrash expands or collapses the crash block at crashIdx
+func (m *Model) toggleCrash(crashIdx int) {
+	if crashIdx < 0 || crashIdx >= len(m.crashes) {
+		return
+	}
+	if m.expandedCrashes == nil {
+		m.expandedCrashes = make(map[int]bool)
+	}
+	start := m.crashes[crashIdx].Start
+	m.expandedCrashes[start] = !m.expandedCrashes[start]
+	m.rebuildLines()
+	m.clampCursor()
+	m.clampOffset()
+}
+
+// renderCrashLine renders the folded header of a crash block
+func (m Model) renderCrashLine(line Line, isSelected bool) string {
+	t := m.theme()
+	var prefix string
+	if line.AttrIdx <= 0 {
+		expandIcon := "▸"
+		if line.DiagIdx >= 0 && line.DiagIdx < len(m.crashes) && m.expandedCrashes[m.crashes[line.DiagIdx].Start] {
+			expandIcon = "▾"
+		}
+		prefix = fmt.Sprintf("%s ☠ ", expandIcon)
+	} else {
+		prefix = "    "
+	}
+
+	if isSelected {
+		return t.Selected.Render("► " + prefix + line.Content)
+	}
+	return "  " + t.Error.Render(prefix+line.Content)
+}
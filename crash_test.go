@@ -0,0 +1,135 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const pluginPanicOutput = `aws_instance.web: Refreshing state... [id=i-0123456789]
+Stack trace from the terraform-provider-aws_v5.31.0_x5 plugin:
+panic: runtime error: invalid memory address or nil pointer dereference
+[signal SIGSEGV: segmentation violation code=0x1 addr=0x0 pc=0x5f3c2a1]
+goroutine 121 [running]:
+github.com/hashicorp/terraform-provider-aws/internal/service/ec2.resourceInstanceRead(0xc000a1e000, 0xc0010b6000)
+	/opt/teamcity-agent/work/internal/service/ec2/ec2_instance.go:1245 +0x1a4
+github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema.(*Resource).read(0xc000a1e000)
+	/opt/teamcity-agent/work/helper/schema/resource.go:783 +0x178
+created by google.golang.org/grpc.(*Server).serveStreams.func1
+	/opt/teamcity-agent/work/server.go:938 +0x9e
+Error: The terraform-provider-aws_v5.31.0_x5 plugin crashed!
+This is always indicative of a bug within the plugin.`
+
+func TestDetectCrashes_PluginPanic(t *testing.T) {
+	logs := strings.Split(pluginPanicOutput, "\n")
+
+	crashes := detectCrashes(logs, nil)
+	if len(crashes) != 1 {
+		t.Fatalf("expected 1 crash, got %d", len(crashes))
+	}
+
+	c := crashes[0]
+	if c.Start != 1 || c.End != 11 {
+		t.Errorf("expected crash to span logs [1, 11), got [%d, %d)", c.Start, c.End)
+	}
+	if c.Message != "runtime error: invalid memory address or nil pointer dereference" {
+		t.Errorf("unexpected panic message %q", c.Message)
+	}
+	if c.TopFrame != "github.com/hashicorp/terraform-provider-aws/internal/service/ec2.resourceInstanceRead" {
+		t.Errorf("unexpected top frame %q", c.TopFrame)
+	}
+	if c.Provider != "terraform-provider-aws_v5.31.0_x5" {
+		t.Errorf("unexpected provider %q", c.Provider)
+	}
+}
+
+func TestDetectCrashes_CoreCrashLog(t *testing.T) {
+	logs := []string{
+		"Terraform crashed! This is always indicative of a bug within Terraform.",
+		"A crash log has been placed at \"crash.log\" relative to your current",
+		"working directory. It would be immensely helpful if you could please",
+		"panic: assignment to entry in nil map",
+		"goroutine 1 [running]:",
+		"github.com/hashicorp/terraform/internal/terraform.(*Graph).walk(0x0)",
+		"\t/home/circleci/project/internal/terraform/graph.go:43 +0x2d",
+		"Releasing state lock. This may take a few moments...",
+	}
+
+	crashes := detectCrashes(logs, nil)
+	if len(crashes) != 1 {
+		t.Fatalf("expected 1 crash, got %d", len(crashes))
+	}
+	if crashes[0].End != 7 {
+		t.Errorf("expected crash to end before trailing output, got end %d", crashes[0].End)
+	}
+	if crashes[0].CrashLog != "crash.log" {
+		t.Errorf("expected crash log path, got %q", crashes[0].CrashLog)
+	}
+}
+
+func TestDetectCrashes_BannerWithoutPanic(t *testing.T) {
+	if crashes := detectCrashes([]string{"Terraform crashed! see above"}, nil); len(crashes) != 0 {
+		t.Errorf("expected no crash without a panic, got %d", len(crashes))
+	}
+}
+
+func TestCrashFoldingInLogView(t *testing.T) {
+	m := &Model{
+		tab:   TabLog,
+		width: 300,
+		logs:  strings.Split(pluginPanicOutput, "\n"),
+	}
+	m.rebuildLines()
+
+	if len(m.lines) != 4 { // refresh line, folded crash, two trailing lines
+		t.Fatalf("expected 4 lines with the stack trace folded, got %d", len(m.lines))
+	}
+	if m.lines[1].Type != LineTypeCrash {
+		t.Fatalf("expected crash header at line 1, got %v", m.lines[1].Type)
+	}
+	if !strings.Contains(m.renderHeader(), "CRASHED") {
+		t.Error("expected fatal marker in header")
+	}
+
+	m.toggleExpand(1)
+	if len(m.lines) != 14 {
+		t.Errorf("expected expanded crash to show all 10 stack lines, got %d lines", len(m.lines))
+	}
+	m.toggleExpand(1)
+	if len(m.lines) != 4 {
+		t.Errorf("expected crash to fold again, got %d lines", len(m.lines))
+	}
+}
+
+func TestDetectCrashes_DetailsForEveryCrash(t *testing.T) {
+	first := strings.Split(pluginPanicOutput, "\n")
+	second := strings.ReplaceAll(pluginPanicOutput, "terraform-provider-aws_v5.31.0_x5", "terraform-provider-google_v5.0.0_x5")
+	logs := append(append(first, "A crash log has been placed at \"aws-crash.log\""), strings.Split(second, "\n")...)
+	logs = append(logs, "A crash log has been placed at \"google-crash.log\"")
+
+	crashes := detectCrashes(logs, nil)
+	if len(crashes) != 2 {
+		t.Fatalf("expected 2 crashes, got %d", len(crashes))
+	}
+	if crashes[0].CrashLog != "aws-crash.log" || crashes[1].CrashLog != "google-crash.log" {
+		t.Errorf("expected each crash to get its own crash log, got %q and %q", crashes[0].CrashLog, crashes[1].CrashLog)
+	}
+	if !strings.Contains(crashes[1].Provider, "google") {
+		t.Errorf("expected the second crash's provider, got %q", crashes[1].Provider)
+	}
+}
+
+func TestScanCrashesIncrementally(t *testing.T) {
+	logs := append([]string{"aws_vpc.main: Creating..."}, strings.Split(pluginPanicOutput, "\n")...)
+	m := Model{tab: TabLog, width: 120}
+	for _, line := range logs {
+		m.logs = append(m.logs, line)
+		m.rebuildLines()
+	}
+	want := detectCrashes(logs, nil)
+	if len(m.crashes) != 1 || m.crashes[0] != want[0] {
+		t.Fatalf("expected streaming to find the same crash as a full scan, got %+v, want %+v", m.crashes, want)
+	}
+	if m.crashScan != len(logs) || m.crashOpen {
+		t.Errorf("expected every line scanned once the crash ended, scan at %d of %d", m.crashScan, len(logs))
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// similarPattern matches Terraform's consolidated warning note, e.g.
+	// "(and 12 more similar warnings elsewhere)".
+	similarPattern = regexp.MustCompile(`\(and (\d+) more similar (?:warnings?|errors?) elsewhere\)`)
+	// withPattern extracts the resource address from a "with aws_instance.web," line
+	withPattern = regexp.MustCompile(`^\s*with\s+(.+?),?\s*$`)
+)
+
+// DiagnosticGroup collects diagnostics that share a severity and summary
+type DiagnosticGroup struct {
+	Severity string
+	Summary  string
+	Indices  []int // Indices into the diagnostics slice, in arrival order
+	Similar  int   // Occurrences Terraform reported as "N more similar ... elsewhere"
+}
+
+// Total returns the number of occurrences represented by the group
+func (g DiagnosticGroup) Total() int {
+	return len(g.Indices) + g.Similar
+}
+
+// groupDiagnostics groups diagnostics by severity and summary, ordered by first occurrence
+func groupDiagnostics(diags []Diagnostic) []DiagnosticGroup {
+	var groups []DiagnosticGroup
+	byKey := make(map[string]int)
+
+	for i, d := range diags {
+		key := d.Severity + "\x00" + d.Summary
+		idx, ok := byKey[key]
+		if !ok {
+			idx = len(groups)
+			byKey[key] = idx
+			groups = append(groups, DiagnosticGroup{Severity: d.Severity, Summary: d.Summary})
+		}
+		groups[idx].Indices = append(groups[idx].Indices, i)
+		groups[idx].Similar += similarCount(d)
+	}
+	return groups
+}
+
+// similarCount returns the number from a "(and N more similar ... elsewhere)" detail line
+func similarCount(d Diagnostic) int {
+	for _, detail := range d.Detail {
+		if match := similarPattern.FindStringSubmatch(stripANSI(detail.Content)); match != nil {
+			if n, err := strconv.Atoi(match[1]); err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// occurrenceLabel summarises where a diagnostic occurred, using its
+// "with <address>," and "on <file> line <n>" detail lines.
+func occurrenceLabel(d Diagnostic) string {
+	var parts []string
+	for _, detail := range d.Detail {
+		clean := stripANSI(detail.Content)
+		if match := withPattern.FindStringSubmatch(clean); match != nil && len(parts) == 0 {
+			parts = append(parts, match[1])
+			continue
+		}
+		if loc, ok := parseLocation(clean); ok {
+			parts = append(parts, fmt.Sprintf("%s line %d", loc.File, loc.Line))
+			break
+		}
+	}
+	if len(parts) == 0 {
+		return "(no location)"
+	}
+	return strings.Join(parts, " — ")
+}
+
+// diagnosticCounts returns the number of unique diagnostic groups and total occurrences
+func diagnosticCounts(groups []DiagnosticGroup) (unique, total int) {
+	for _, g := range groups {
+		total += g.Total()
+	}
+	return len(groups), total
+}
+
+// appendDiagnosticGroupLines emits the display lines for a diagnostic group:
+// the first occurrence's summary and details, followed by one line per
+// occurrence location when the diagnostic was repeated. Later occurrences
+// expand to show their own details below their location.
+func (m *Model) appendDiagnosticGroupLines(g DiagnosticGroup) {
+	i := g.Indices[0]
+	diag := m.diagnostics[i]
+
+	// Wrap summary (accounting for 4 chars prefix: "▸ ✗ ")
+	wrappedSummary := wrapText(diag.Summary, m.width-4, 0)
+	for wIdx, summaryLine := range wrappedSummary {
+		m.lines = append(m.lines, Line{
+			Type:        LineTypeDiagnostic,
+			DiagIdx:     i,
+			ResourceIdx: -1,
+			AttrIdx:     wIdx,
+			Content:     summaryLine,
+		})
+	}
+
+	// Collapsed diagnostics show only their summary
+	if !diag.Expanded {
+		return
+	}
+
+	m.appendDiagnosticDetailLines(i)
+
+	if diag.ShowSource {
+		m.appendSourceLines(i)
+	}
+	if diag.ShowHint {
+		m.appendHintLines(i)
+	}
+	m.appendLockCardLines(i)
+
+	if g.Total() <= 1 {
+		return
+	}
+	for _, idx := range g.Indices {
+		label := "↳ " + occurrenceLabel(m.diagnostics[idx])
+		for _, w := range wrapText(label, m.width-4, 2) {
+			m.lines = append(m.lines, Line{
+				Type:        LineTypeOccurrence,
+				DiagIdx:     idx,
+				ResourceIdx: -1,
+				AttrIdx:     0,
+				Content:     w,
+			})
+		}
+		if idx != i && m.diagnostics[idx].Expanded {
+			m.appendDiagnosticDetailLines(idx)
+		}
+	}
+	if g.Similar > 0 {
+		m.lines = append(m.lines, Line{
+			Type:        LineTypeOccurrence,
+			DiagIdx:     i,
+			ResourceIdx: -1,
+			AttrIdx:     -1,
+			Content:     fmt.Sprintf("↳ %d more similar elsewhere", g.Similar),
+		})
+	}
+}
+
+// appendDiagnosticDetailLines emits the detail lines of the diagnostic at i,
+// preserving guide colors (│, ├, ─, ╵) and underline markers (^, ~)
+func (m *Model) appendDiagnosticDetailLines(i int) {
+	for j, detail := range m.diagnostics[i].Detail {
+		// Wrap diagnostic details (accounting for 4 spaces padding in render)
+		for _, w := range wrapText(detail.Content, m.width-4, 0) {
+			m.lines = append(m.lines, Line{
+				Type:        LineTypeDiagnosticDetail,
+				DiagIdx:     i,
+				ResourceIdx: -1,
+				AttrIdx:     j,
+				Content:     w,
+			})
+		}
+	}
+}
+
+// groupTotal returns the occurrence count of the group whose first member is
+// diagIdx. The chronological Log layout shows every diagnostic on its own.
+func (m Model) groupTotal(diagIdx int) int {
+	if m.tab == TabLog && m.chronological {
+		return 1
+	}
+	for _, g := range m.diagGroups {
+		if g.Indices[0] == diagIdx {
+			return g.Total()
+		}
+	}
+	return 1
+}
+
+// renderOccurrenceLine renders the location of one occurrence of a grouped diagnostic
+func (m Model) renderOccurrenceLine(line Line, isSelected bool) string {
+	t := m.theme()
+	if isSelected {
+		return t.Selected.Render("►   " + line.Content)
+	}
+	return "    " + t.Dim.Render(line.Content)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func invalidValueDiag(idx int) Diagnostic {
+	return Diagnostic{
+		Severity: "error",
+		Summary:  "Invalid value",
+		Detail: []DiagnosticLine{
+			{Content: fmt.Sprintf("  with aws_subnet.private[%d],", idx)},
+			{Content: "  on network.tf line 14, in resource \"aws_subnet\" \"private\":", IsMarker: true},
+		},
+		Expanded: true,
+	}
+}
+
+func TestGroupDiagnostics(t *testing.T) {
+	diags := []Diagnostic{
+		invalidValueDiag(0),
+		{Severity: "warning", Summary: "Deprecated attribute", Detail: []DiagnosticLine{
+			{Content: "(and 12 more similar warnings elsewhere)"},
+		}},
+		invalidValueDiag(1),
+		invalidValueDiag(2),
+		{Severity: "warning", Summary: "Invalid value"}, // Same summary, different severity
+	}
+
+	groups := groupDiagnostics(diags)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(groups))
+	}
+	if got := groups[0].Indices; len(got) != 3 || got[0] != 0 || got[2] != 3 {
+		t.Errorf("expected first group to contain diagnostics 0, 2, 3, got %v", got)
+	}
+	if groups[1].Total() != 13 {
+		t.Errorf("expected consolidated warning to count 13 occurrences, got %d", groups[1].Total())
+	}
+
+	unique, total := diagnosticCounts(groups)
+	if unique != 3 || total != 17 {
+		t.Errorf("expected 3 unique / 17 total, got %d / %d", unique, total)
+	}
+}
+
+func TestOccurrenceLabel(t *testing.T) {
+	label := occurrenceLabel(invalidValueDiag(7))
+	if label != "aws_subnet.private[7] — network.tf line 14" {
+		t.Errorf("unexpected label %q", label)
+	}
+	if label := occurrenceLabel(Diagnostic{Summary: "x"}); label != "(no location)" {
+		t.Errorf("expected placeholder label, got %q", label)
+	}
+}
+
+func TestRebuildLines_GroupsRepeatedDiagnostics(t *testing.T) {
+	m := &Model{tab: TabLog, width: 120}
+	for i := 0; i < 40; i++ {
+		m.diagnostics = append(m.diagnostics, invalidValueDiag(i))
+	}
+	m.rebuildLines()
+
+	var headers, occurrences int
+	for _, l := range m.lines {
+		switch l.Type {
+		case LineTypeDiagnostic:
+			headers++
+		case LineTypeOccurrence:
+			occurrences++
+		}
+	}
+	if headers != 1 {
+		t.Errorf("expected a single diagnostic header, got %d", headers)
+	}
+	if occurrences != 40 {
+		t.Errorf("expected 40 occurrence lines, got %d", occurrences)
+	}
+
+	header := m.renderDiagnosticLine(m.lines[0], false)
+	if !strings.Contains(header, "×40") {
+		t.Errorf("expected occurrence count in header, got %q", header)
+	}
+	if footer := m.renderFooter(); !strings.Contains(footer, "1 unique / 40 total") {
+		t.Errorf("expected unique/total counts in footer, got %q", footer)
+	}
+}
+
+func TestOccurrenceExpandsItsOwnDetails(t *testing.T) {
+	m := &Model{tab: TabLog, width: 120, height: 30}
+	for i := 0; i < 3; i++ {
+		d := invalidValueDiag(i)
+		d.Expanded = i == 0
+		m.diagnostics = append(m.diagnostics, d)
+	}
+	m.rebuildLines()
+
+	lineOf := func(typ LineType, diagIdx int) int {
+		for i, l := range m.lines {
+			if l.Type == typ && l.DiagIdx == diagIdx {
+				return i
+			}
+		}
+		return -1
+	}
+	if lineOf(LineTypeDiagnosticDetail, 1) >= 0 {
+		t.Fatal("expected later occurrences to start collapsed")
+	}
+
+	m.toggleExpand(lineOf(LineTypeOccurrence, 1))
+	detail := lineOf(LineTypeDiagnosticDetail, 1)
+	if detail < 0 || detail < lineOf(LineTypeOccurrence, 1) || !strings.Contains(m.lines[detail].Content, "private[1]") {
+		t.Fatalf("expected the second occurrence's details below its location, got line %d", detail)
+	}
+	if lineOf(LineTypeDiagnosticDetail, 2) >= 0 {
+		t.Error("expected other occurrences to stay collapsed")
+	}
+
+	m.toggleExpand(detail)
+	if lineOf(LineTypeDiagnosticDetail, 1) >= 0 {
+		t.Error("expected toggling a detail line to collapse its occurrence")
+	}
+	if m.cursor != lineOf(LineTypeOccurrence, 1) {
+		t.Errorf("expected the cursor on the collapsed occurrence, got line %d", m.cursor)
+	}
+}
+
+func logTabDiagModel() Model {
+	m := Model{tab: TabLog, width: 120, height: 30, ready: true}
+	m.logs = []string{"aws_instance.web: Creating..."}
+	m.diagnostics = []Diagnostic{
+		*parseDiagnosticBlock([]string{"Error: Invalid reference", "", "  on main.tf line 3:", "   3:   ami = var.amiid"}),
+		*parseDiagnosticBlock([]string{"Warning: Deprecated attribute", "", "  on main.tf line 7:", "   7:   acl = \"private\""}),
+	}
+	m.rebuildLines()
+	return m
+}
+
+func diagLineCounts(m Model) (summaries, details int) {
+	for _, l := range m.lines {
+		switch l.Type {
+		case LineTypeDiagnostic:
+			summaries++
+		case LineTypeDiagnosticDetail:
+			details++
+		}
+	}
+	return summaries, details
+}
+
+func TestLogTabWarningsStartCollapsed(t *testing.T) {
+	m := logTabDiagModel()
+	if !m.diagnostics[0].Expanded || m.diagnostics[1].Expanded {
+		t.Fatalf("expected errors expanded and warnings collapsed, got %v / %v", m.diagnostics[0].Expanded, m.diagnostics[1].Expanded)
+	}
+	for _, l := range m.lines {
+		if l.Type == LineTypeDiagnosticDetail && l.DiagIdx == 1 {
+			t.Error("expected a collapsed warning to show only its summary")
+		}
+	}
+}
+
+func TestLogTabToggleDiagnostic(t *testing.T) {
+	m := logTabDiagModel()
+	warning := -1
+	for i, l := range m.lines {
+		if l.Type == LineTypeDiagnostic && l.DiagIdx == 1 {
+			warning = i
+		}
+	}
+	m.cursor = warning
+	m = typeKeys(m, "enter")
+	if !m.diagnostics[1].Expanded {
+		t.Fatal("expected Enter to expand the warning in the Log tab")
+	}
+
+	// Collapsing from a detail line returns the cursor to the summary
+	m.cursor = warning + 1
+	m = typeKeys(m, "enter")
+	if m.diagnostics[1].Expanded || m.cursor != warning {
+		t.Errorf("expected collapse from detail line, expanded=%v cursor=%d", m.diagnostics[1].Expanded, m.cursor)
+	}
+}
+
+func TestLogTabClickTogglesDiagnostic(t *testing.T) {
+	m := logTabDiagModel()
+	m.cursor = 1 // Error summary, after the log line
+	updated, _ := m.Update(tea.MouseMsg{X: 4, Y: 3, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	m = updated.(Model)
+	if m.diagnostics[0].Expanded {
+		t.Error("expected clicking the selected diagnostic to collapse it")
+	}
+}
+
+func TestLogTabExpandCollapseAll(t *testing.T) {
+	m := logTabDiagModel()
+	m = typeKeys(m, "c")
+	if _, details := diagLineCounts(m); details != 0 {
+		t.Errorf("expected c to collapse every diagnostic, got %d detail lines", details)
+	}
+	m = typeKeys(m, "e")
+	if m.diagnostics[0].Expanded != true || m.diagnostics[1].Expanded != true {
+		t.Error("expected e to expand every diagnostic")
+	}
+	if summaries, details := diagLineCounts(m); summaries != 2 || details == 0 {
+		t.Errorf("expected summaries with details, got %d / %d", summaries, details)
+	}
+}
@@ -61,8 +61,8 @@ func TestLongDiagnosticMessage(t *testing.T) {
 func TestDiagnosticSummaryWrapping(t *testing.T) {
 	lipgloss.SetColorProfile(termenv.ANSI)
 	m := &Model{
-		width:    20,
-		showLogs: true, // Diagnostics are now shown in LOG view only
+		width: 20,
+		tab:   TabLog, // Diagnostics are now shown in LOG view only
 		diagnostics: []Diagnostic{
 			{
 				Severity: "error",
@@ -146,23 +146,23 @@ func TestRealWorldDiagnosticParsing(t *testing.T) {
 }
 
 // TestDiagnosticsInLogViewNotPlanView verifies that diagnostics are rendered
-// in LOG view (showLogs=true) but NOT in PLAN view (showLogs=false)
+// in the Log tab but NOT in the Plan tab
 func TestDiagnosticsInLogViewNotPlanView(t *testing.T) {
 	testCases := []struct {
 		name            string
-		showLogs        bool
+		tab             Tab
 		expectDiags     bool
 		expectResources bool
 	}{
 		{
 			name:            "LOG view shows diagnostics",
-			showLogs:        true,
+			tab:             TabLog,
 			expectDiags:     true,
 			expectResources: false,
 		},
 		{
 			name:            "PLAN view shows only resources",
-			showLogs:        false,
+			tab:             TabPlan,
 			expectDiags:     false,
 			expectResources: true,
 		},
@@ -172,7 +172,7 @@ func TestDiagnosticsInLogViewNotPlanView(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			m := &Model{
 				renderingMode: RenderingModeDashboard,
-				showLogs:      tc.showLogs,
+				tab:           tc.tab,
 				width:         80,
 				diagnostics: []Diagnostic{
 					{
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// confirmDialog is a modal that requires the user to type one of the expected
+// answers before an action runs. Esc cancels it.
+type confirmDialog struct {
+	title     string
+	lines     []string // Body text shown above the input
+	expect    []string // Accepted answers; the input must match one exactly
+	input     string
+	mismatch  bool // Last submitted answer did not match
+	onConfirm func(m *Model) tea.Cmd
+}
+
+// handleDialogKey processes keyboard input while a confirmation dialog is open
+func (m Model) handleDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := m.dialog
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.dialog = nil
+
+	case tea.KeyBackspace, tea.KeyDelete:
+		if r := []rune(d.input); len(r) > 0 {
+			d.input = string(r[:len(r)-1])
+		}
+
+	case tea.KeyRunes:
+		d.input += string(msg.Runes)
+
+	case tea.KeySpace:
+		d.input += " "
+
+	case tea.KeyEnter:
+		for _, want := range d.expect {
+			if strings.TrimSpace(d.input) == want {
+				m.dialog = nil
+				return m, d.onConfirm(&m)
+			}
+		}
+		d.mismatch = true
+		d.input = ""
+	}
+
+	return m, nil
+}
+
+// renderDialog renders the confirmation dialog pinned above the footer
+func (m Model) renderDialog() string {
+	t := m.theme()
+	d := m.dialog
+
+	var b strings.Builder
+	b.WriteString(t.HeaderError.Render(d.title))
+	b.WriteString("\n")
+	for _, line := range d.lines {
+		b.WriteString("  " + t.Default.Render(line) + "\n")
+	}
+	if d.mismatch {
+		b.WriteString("  " + t.Error.Render("Answer did not match, try again.") + "\n")
+	}
+	b.WriteString(t.Prompt.Render(">> ") + t.Create.Render(d.input) + t.Dim.Render("█") + t.Dim.Render("  Enter:confirm  Esc:cancel"))
+	return b.String()
+}
+
+// dialogHeight returns the number of lines the open dialog occupies
+func (m Model) dialogHeight() int {
+	if m.dialog == nil {
+		return 0
+	}
+	h := len(m.dialog.lines) + 3 // title, input and separating blank line
+	if m.dialog.mismatch {
+		h++
+	}
+	return h
+}
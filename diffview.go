@@ -0,0 +1,328 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DiffKind classifies a row of the side-by-side diff
+type DiffKind int
+
+const (
+	DiffUnchanged DiffKind = iota
+	DiffChanged
+	DiffAdded
+	DiffRemoved
+	DiffNote // Comments such as "# (3 unchanged attributes hidden)"
+)
+
+// diffSeparator is drawn between the before and after columns
+const diffSeparator = " │ "
+
+var (
+	// diffKeyPattern matches the "key = " prefix of an attribute or map entry
+	diffKeyPattern = regexp.MustCompile(`^("[^"]*"|[\w.-]+)\s*=\s*`)
+	// placeholderPattern matches values Terraform cannot show
+	placeholderPattern = regexp.MustCompile(`\((known after apply|sensitive value|sensitive)\)`)
+)
+
+// DiffRow is one aligned row of the before/after view
+type DiffRow struct {
+	Kind   DiffKind
+	Path   string // Attribute path, e.g. "tags.Name"; list elements share their list's path
+	Depth  int
+	Before string // Text of the before column ("" if absent before)
+	After  string // Text of the after column ("" if absent after)
+}
+
+// splitMarker separates Terraform's change marker from the attribute text.
+// Replacement markers (-/+, +/-) count as changes.
+func splitMarker(text string) (string, string) {
+	for _, m := range []string{"-/+ ", "+/- "} {
+		if strings.HasPrefix(text, m) {
+			return "~", text[len(m):]
+		}
+	}
+	for _, m := range []string{"~ ", "+ ", "- "} {
+		if strings.HasPrefix(text, m) {
+			return m[:1], text[2:]
+		}
+	}
+	return "", text
+}
+
+// buildDiffRows converts a resource's attribute lines into aligned before and
+// after rows. Nesting is derived from Terraform's indentation, changed values
+// ("a -> b") are split across the columns, and runs of removed entries
+// followed by added entries at the same path are paired into changed rows.
+func buildDiffRows(attrs []string) []DiffRow {
+	var rows []DiffRow
+	var pending []int         // Removed rows that a following addition may pair with
+	var stack []string        // Keys of the open blocks
+	var blockMarkers []string // Markers of the open blocks, for their closing lines
+
+	baseCol := -1
+	for _, attr := range attrs {
+		trimmed := strings.TrimLeft(attr, " ")
+		if trimmed == "" {
+			continue
+		}
+		marker, body := splitMarker(trimmed)
+		col := len(attr) - len(trimmed)
+		if marker != "" {
+			col += 2
+		}
+		if baseCol < 0 || col < baseCol {
+			baseCol = col
+		}
+		depth := (col - baseCol) / 4
+
+		// Closing a block takes the marker of the line that opened it
+		if strings.HasPrefix(body, "}") || strings.HasPrefix(body, "]") || strings.HasPrefix(body, ")") {
+			if n := len(stack); n > 0 {
+				stack = stack[:n-1]
+				if marker == "" {
+					marker = blockMarkers[n-1]
+				}
+				blockMarkers = blockMarkers[:n-1]
+			}
+			pending = nil
+			rows = append(rows, diffRowFor(marker, "", body, depth, strings.Join(stack, ".")))
+			continue
+		}
+
+		if strings.HasPrefix(body, "#") {
+			pending = nil
+			rows = append(rows, DiffRow{Kind: DiffNote, Depth: depth, Before: body, After: body})
+			continue
+		}
+
+		key := ""
+		if match := diffKeyPattern.FindStringSubmatch(body); match != nil {
+			key = match[1]
+		} else if strings.HasSuffix(body, "{") {
+			// Nested blocks: `block {` or `block "label" {`
+			key = strings.Fields(body)[0]
+		}
+		path := strings.Join(append(append([]string{}, stack...), key), ".")
+		if key == "" {
+			path = strings.Join(stack, ".")
+		}
+		row := diffRowFor(marker, key, body, depth, path)
+
+		// Pair an addition with an earlier removal of the same path
+		paired := false
+		if row.Kind == DiffAdded {
+			for i, idx := range pending {
+				if rows[idx].Path == row.Path && rows[idx].Depth == row.Depth {
+					rows[idx].Kind = DiffChanged
+					rows[idx].After = row.After
+					pending = append(pending[:i], pending[i+1:]...)
+					paired = true
+					break
+				}
+			}
+		}
+		if !paired {
+			switch row.Kind {
+			case DiffRemoved:
+				pending = append(pending, len(rows))
+			case DiffAdded:
+			default:
+				pending = nil
+			}
+			rows = append(rows, row)
+		}
+
+		if strings.HasSuffix(body, "{") || strings.HasSuffix(body, "[") || strings.HasSuffix(body, "(") {
+			stack = append(stack, key)
+			blockMarkers = append(blockMarkers, marker)
+			pending = nil
+		}
+	}
+	return rows
+}
+
+// diffRowFor builds the row for a single attribute line
+func diffRowFor(marker, key, body string, depth int, path string) DiffRow {
+	row := DiffRow{Path: path, Depth: depth}
+	switch marker {
+	case "+":
+		row.Kind = DiffAdded
+		row.After = body
+	case "-":
+		row.Kind = DiffRemoved
+		row.Before = strings.TrimSuffix(body, " -> null")
+	case "~":
+		row.Kind = DiffChanged
+		row.Before, row.After = body, body
+		if before, after, ok := strings.Cut(body, " -> "); ok {
+			prefix := ""
+			if loc := diffKeyPattern.FindStringIndex(before); loc != nil && key != "" {
+				prefix = before[:loc[1]]
+			}
+			row.Before = before
+			row.After = prefix + after
+		}
+	default:
+		row.Before, row.After = body, body
+	}
+	return row
+}
+
+// diffSideBySide reports whether a resource is shown as a before/after diff
+func (m Model) diffSideBySide(rc ResourceChange) bool {
+	return m.sideBySide && (rc.Action == "update" || rc.Action == "replace")
+}
+
+// diffRowsFor returns the diff rows of a resource, cached per rebuild
+func (m Model) diffRowsFor(resIdx int) []DiffRow {
+	if rows, ok := m.diffRows[resIdx]; ok {
+		return rows
+	}
+	rows := buildDiffRows(m.resources[resIdx].Attributes)
+	if m.diffRows != nil {
+		m.diffRows[resIdx] = rows
+	}
+	return rows
+}
+
+// diffColumnWidth returns the width of each column for a body of the given width
+func diffColumnWidth(width int) int {
+	w := (width - 4 - len([]rune(diffSeparator))) / 2
+	if w < 8 {
+		w = 8
+	}
+	return w
+}
+
+// diffCell formats one side of a row with its marker and nesting indent
+func diffCell(marker, text string, depth int) string {
+	if text == "" {
+		return ""
+	}
+	return marker + " " + strings.Repeat("    ", depth) + text
+}
+
+// diffLines lays out a resource's diff rows for a body of the given width.
+// A header row comes first (AttrIdx -1), and cells too wide for their column
+// wrap onto extra lines of the same row.
+func (m Model) diffLines(resIdx, width int) []Line {
+	colWidth := diffColumnWidth(width)
+	lines := []Line{{
+		Type:        LineTypeDiff,
+		ResourceIdx: resIdx,
+		DiagIdx:     -1,
+		AttrIdx:     -1,
+		Content:     "before",
+		Aside:       "after",
+	}}
+
+	for i, row := range m.diffRowsFor(resIdx) {
+		beforeMarker, afterMarker := " ", " "
+		switch row.Kind {
+		case DiffChanged:
+			// Blocks containing changes keep the same text on both sides
+			if row.Before != row.After {
+				beforeMarker, afterMarker = "-", "+"
+			}
+		case DiffAdded:
+			afterMarker = "+"
+		case DiffRemoved:
+			beforeMarker = "-"
+		}
+		before := diffCell(beforeMarker, row.Before, row.Depth)
+		after := diffCell(afterMarker, row.After, row.Depth)
+
+		left := wrapText(before, colWidth, getIndentForLine(before))
+		right := wrapText(after, colWidth, getIndentForLine(after))
+		for k := 0; k < len(left) || k < len(right); k++ {
+			line := Line{Type: LineTypeDiff, ResourceIdx: resIdx, DiagIdx: -1, AttrIdx: i}
+			if k < len(left) {
+				line.Content = left[k]
+			}
+			if k < len(right) {
+				line.Aside = right[k]
+			}
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// styleDiffCell renders one column of a diff line. Markers take the column's
+// color and placeholders are set apart so they are not read as real values.
+func (m Model) styleDiffCell(text string, kind DiffKind, before bool, width int) string {
+	t := m.theme()
+	base := t.Default
+	switch kind {
+	case DiffUnchanged:
+		base = t.Dim
+	case DiffNote:
+		return fitWidth(t.Dim.Render(text), width)
+	}
+
+	var b strings.Builder
+	rest := text
+	if strings.HasPrefix(rest, "- ") && before {
+		b.WriteString(t.RemoveAttr.Render("-"))
+		rest = rest[1:]
+	} else if strings.HasPrefix(rest, "+ ") && !before {
+		b.WriteString(t.AddAttr.Render("+"))
+		rest = rest[1:]
+	}
+
+	pos := 0
+	for _, loc := range placeholderPattern.FindAllStringIndex(rest, -1) {
+		b.WriteString(base.Render(rest[pos:loc[0]]))
+		b.WriteString(m.placeholderStyle(rest[loc[0]:loc[1]]).Render(rest[loc[0]:loc[1]]))
+		pos = loc[1]
+	}
+	b.WriteString(base.Render(rest[pos:]))
+	return fitWidth(b.String(), width)
+}
+
+// placeholderStyle returns the style for an unknown or sensitive value
+func (m Model) placeholderStyle(placeholder string) lipgloss.Style {
+	t := m.theme()
+	if strings.Contains(placeholder, "sensitive") {
+		return t.Warning.Copy().Italic(true)
+	}
+	return t.Import.Copy().Italic(true)
+}
+
+// renderDiffLine renders a line of the side-by-side diff for a body of the given width
+func (m Model) renderDiffLine(line Line, isSelected bool, width int) string {
+	t := m.theme()
+	colWidth := diffColumnWidth(width)
+
+	prefix := "    "
+	if isSelected {
+		prefix = t.Selected.Render("►   ")
+	}
+	if line.AttrIdx < 0 {
+		return prefix + fitWidth(t.Dim.Copy().Bold(true).Render(line.Content), colWidth) +
+			t.Dim.Render(diffSeparator) + t.Dim.Copy().Bold(true).Render(line.Aside)
+	}
+
+	kind := DiffUnchanged
+	if rows := m.diffRowsFor(line.ResourceIdx); line.AttrIdx < len(rows) {
+		kind = rows[line.AttrIdx].Kind
+	}
+	return prefix + m.styleDiffCell(line.Content, kind, true, colWidth) +
+		t.Dim.Render(diffSeparator) + m.styleDiffCell(line.Aside, kind, false, colWidth)
+}
+
+// toggleSideBySide switches update and replace resources between the inline
+// and before/after views
+func (m *Model) toggleSideBySide() {
+	if m.tab != TabPlan {
+		return
+	}
+	m.sideBySide = !m.sideBySide
+	m.rebuildLines()
+	m.clampCursor()
+	m.clampOffset()
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildDiffRowsSplitsChangedValues(t *testing.T) {
+	rows := buildDiffRows([]string{
+		`      ~ instance_type = "t2.micro" -> "t2.small"`,
+		`      - monitoring    = true -> null`,
+		`      + ebs_optimized = true`,
+		`        id            = "i-0abc"`,
+	})
+	want := []DiffRow{
+		{Kind: DiffChanged, Path: "instance_type", Before: `instance_type = "t2.micro"`, After: `instance_type = "t2.small"`},
+		{Kind: DiffRemoved, Path: "monitoring", Before: `monitoring    = true`},
+		{Kind: DiffAdded, Path: "ebs_optimized", After: `ebs_optimized = true`},
+		{Kind: DiffUnchanged, Path: "id", Before: `id            = "i-0abc"`, After: `id            = "i-0abc"`},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d: %+v", len(rows), len(want), rows)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestBuildDiffRowsPairsListElements(t *testing.T) {
+	rows := buildDiffRows([]string{
+		`      ~ security_groups = [`,
+		`          - "sg-111",`,
+		`          - "sg-222",`,
+		`          + "sg-333",`,
+		`            "sg-444",`,
+		`        ]`,
+	})
+	if len(rows) != 5 {
+		t.Fatalf("expected removal and addition paired into one row, got %d: %+v", len(rows), rows)
+	}
+	if rows[1].Kind != DiffChanged || rows[1].Before != `"sg-111",` || rows[1].After != `"sg-333",` {
+		t.Errorf("expected first removal paired with the addition, got %+v", rows[1])
+	}
+	if rows[2].Kind != DiffRemoved || rows[2].After != "" {
+		t.Errorf("expected unpaired removal to stay on the before side, got %+v", rows[2])
+	}
+	if rows[1].Path != "security_groups" || rows[1].Depth != 1 {
+		t.Errorf("expected list elements under security_groups at depth 1, got %+v", rows[1])
+	}
+	if rows[4].Before != "]" || rows[4].Depth != 0 {
+		t.Errorf("expected closing bracket at depth 0, got %+v", rows[4])
+	}
+}
+
+func TestBuildDiffRowsAlignsNestedPaths(t *testing.T) {
+	rows := buildDiffRows([]string{
+		`      ~ tags = {`,
+		`          ~ "Name" = "web" -> "web-2"`,
+		`          - "Owner" = "alice" -> null`,
+		`          + "Team"  = "platform"`,
+		`        }`,
+		`      + root_block_device {`,
+		`          + volume_size = 50`,
+		`        }`,
+	})
+	if rows[1].Path != `tags."Name"` {
+		t.Errorf("expected nested path, got %q", rows[1].Path)
+	}
+	if rows[2].Kind != DiffRemoved || rows[3].Kind != DiffAdded {
+		t.Errorf("expected different keys to stay unpaired, got %+v / %+v", rows[2], rows[3])
+	}
+	if last := rows[len(rows)-1]; last.Kind != DiffAdded || last.After != "}" || last.Before != "" {
+		t.Errorf("expected closing brace of an added block on the after side only, got %+v", last)
+	}
+}
+
+func TestBuildDiffRowsPairsSameKeyAcrossRemoveAdd(t *testing.T) {
+	rows := buildDiffRows([]string{
+		`      - name = "old"`,
+		`      + name = "new"`,
+	})
+	if len(rows) != 1 || rows[0].Kind != DiffChanged || rows[0].After != `name = "new"` {
+		t.Errorf("expected remove/add of the same key paired, got %+v", rows)
+	}
+}
+
+// diffResources are an expanded update and create
+var diffResources = []ResourceChange{
+	{Address: "aws_instance.web", Action: "update", ActionText: planActionTexts["update"], Expanded: true, Attributes: []string{
+		`      ~ instance_type = "t2.micro" -> "t2.small"`,
+		`      ~ private_ip    = "10.0.0.5" -> (known after apply)`,
+		`      ~ password      = (sensitive value)`,
+	}},
+	{Address: "aws_vpc.main", Action: "create", ActionText: planActionTexts["create"], Expanded: true, Attributes: []string{
+		`      + cidr_block = "10.0.0.0/16"`,
+	}},
+}
+
+func TestSideBySideToggle(t *testing.T) {
+	m := planModel(100, 30, diffResources...)
+	m = typeKeys(m, "d")
+	if !m.sideBySide {
+		t.Fatal("expected d to enable the side-by-side view")
+	}
+
+	var diffLines, attrLines int
+	for _, l := range m.lines {
+		switch l.Type {
+		case LineTypeDiff:
+			diffLines++
+		case LineTypeAttribute:
+			attrLines++
+		}
+	}
+	if diffLines != 4 {
+		t.Errorf("expected header plus 3 rows for the update, got %d diff lines", diffLines)
+	}
+	if attrLines != 1 {
+		t.Errorf("expected creates to stay inline, got %d attribute lines", attrLines)
+	}
+
+	view := stripANSI(m.View())
+	for _, want := range []string{"before", "after", `- instance_type = "t2.micro"`, `+ instance_type = "t2.small"`, "(known after apply)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q in view:\n%s", want, view)
+		}
+	}
+
+	m = typeKeys(m, "d")
+	for _, l := range m.lines {
+		if l.Type == LineTypeDiff {
+			t.Fatal("expected d to restore the inline view")
+		}
+	}
+}
+
+func TestSideBySidePlaceholdersStyled(t *testing.T) {
+	m := planModel(100, 30, diffResources...)
+	m.renderingMode = RenderingModeHighContrast
+	cell := m.styleDiffCell(`+ private_ip = (known after apply)`, DiffChanged, false, 60)
+	want := m.placeholderStyle("(known after apply)").Render("(known after apply)")
+	if !strings.Contains(cell, want) {
+		t.Errorf("expected placeholder rendered with its own style, got %q", cell)
+	}
+	if m.placeholderStyle("(sensitive value)").GetItalic() != true {
+		t.Error("expected sensitive placeholder to be styled distinctly")
+	}
+}
+
+func TestSideBySideLeavesThemeUnchanged(t *testing.T) {
+	m := planModel(100, 30, diffResources...)
+	m = typeKeys(m, "d")
+	m.theme()
+	_ = m.View()
+	th := m.theme()
+	if th.Dim.GetBold() || th.Warning.GetItalic() || th.Import.GetItalic() {
+		t.Error("expected rendering the diff not to change the theme's styles")
+	}
+}
+
+func TestSideBySideSearchSeesBothColumns(t *testing.T) {
+	m := planModel(100, 30, diffResources...)
+	m = typeKeys(m, "d", "/", "t2.small", "enter")
+	line := m.lines[m.cursor]
+	if line.Type != LineTypeDiff || !strings.Contains(line.Aside, "t2.small") {
+		t.Errorf("expected search to land on the after column, got %+v", line)
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultEditor is used when $EDITOR is not set
+const defaultEditor = "vi"
+
+// declarationPattern extracts the block kind, resource type and name from a
+// marker such as `on main.tf line 7, in resource "aws_instance" "web":`.
+var declarationPattern = regexp.MustCompile(`in (resource|data) "([^"]+)" "([^"]+)"`)
+
+// editorFinishedMsg is sent when the external editor exits
+type editorFinishedMsg struct {
+	err error
+}
+
+// editorCommand builds the command that opens loc in the user's editor.
+// $EDITOR may contain arguments (e.g. "code --wait"), which are preserved.
+func editorCommand(editor string, loc SourceLocation) *exec.Cmd {
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		fields = []string{defaultEditor}
+	}
+	args := append(append([]string(nil), fields[1:]...), "+"+strconv.Itoa(loc.Line), loc.File)
+	return exec.Command(fields[0], args...)
+}
+
+// locationAt returns the source location associated with the line at lineIdx.
+// Diagnostic lines use their first marker (or the snippet line under the cursor);
+// resource lines use the marker of any diagnostic that names the resource.
+func (m Model) locationAt(lineIdx int) (SourceLocation, bool) {
+	if lineIdx < 0 || lineIdx >= len(m.lines) {
+		return SourceLocation{}, false
+	}
+
+	line := m.lines[lineIdx]
+	switch line.Type {
+	case LineTypeDiagnostic, LineTypeDiagnosticDetail, LineTypeSource, LineTypeOccurrence:
+		if line.DiagIdx < 0 || line.DiagIdx >= len(m.diagnostics) {
+			return SourceLocation{}, false
+		}
+		if line.Type == LineTypeSource && line.AttrIdx >= 0 {
+			if snippet, ok := m.snippets[line.DiagIdx]; ok && line.AttrIdx < len(snippet.Lines) {
+				return SourceLocation{File: snippet.Location.File, Line: snippet.Lines[line.AttrIdx].Number}, true
+			}
+		}
+		if line.Type == LineTypeDiagnosticDetail {
+			diag := m.diagnostics[line.DiagIdx]
+			if line.AttrIdx >= 0 && line.AttrIdx < len(diag.Detail) {
+				if loc, ok := parseLocation(diag.Detail[line.AttrIdx].Content); ok {
+					return loc, true
+				}
+			}
+		}
+		loc, _, ok := diagnosticLocation(m.diagnostics[line.DiagIdx])
+		return loc, ok
+
+	case LineTypeResource, LineTypeAttribute:
+		if line.ResourceIdx >= 0 && line.ResourceIdx < len(m.resources) {
+			return m.resourceLocation(m.resources[line.ResourceIdx].Address)
+		}
+	}
+	return SourceLocation{}, false
+}
+
+// resourceAddress is a parsed resource address such as
+// module.app["a.b"].data.aws_ami.ubuntu[0]
+type resourceAddress struct {
+	modules []string // Module call names from the root, without instance keys
+	data    bool     // A data source rather than a managed resource
+	resType string
+	name    string
+}
+
+// splitAddress splits an address on the dots outside instance keys
+func splitAddress(address string) []string {
+	var parts []string
+	depth, inQuote, start := 0, false, 0
+	for i := 0; i < len(address); i++ {
+		switch c := address[i]; {
+		case inQuote && c == '\\':
+			i++
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == '.' && depth == 0:
+			parts = append(parts, address[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, address[start:])
+}
+
+// parseResourceAddress parses a resource address, dropping instance keys
+func parseResourceAddress(address string) (resourceAddress, bool) {
+	var addr resourceAddress
+	parts := splitAddress(address)
+	for i := range parts {
+		if idx := strings.Index(parts[i], "["); idx >= 0 {
+			parts[i] = parts[i][:idx]
+		}
+	}
+	for len(parts) >= 2 && parts[0] == "module" {
+		addr.modules = append(addr.modules, parts[1])
+		parts = parts[2:]
+	}
+	if len(parts) > 0 && parts[0] == "data" {
+		addr.data = true
+		parts = parts[1:]
+	}
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return resourceAddress{}, false
+	}
+	addr.resType, addr.name = parts[0], parts[1]
+	return addr, true
+}
+
+// modulesManifest is the part of .terraform/modules/modules.json that maps
+// module keys (e.g. "app.db") to their source directories
+type modulesManifest struct {
+	Modules []struct {
+		Key string
+		Dir string
+	}
+}
+
+// commandDir returns the directory the wrapped command runs Terraform in:
+// its -chdir value, or "." when there is none
+func commandDir(command []string) string {
+	for _, a := range command {
+		if dir, ok := strings.CutPrefix(a, "-chdir="); ok {
+			return dir
+		}
+	}
+	return "."
+}
+
+// resolvePath resolves a file path Terraform printed, which is relative to its
+// working directory dir, against terraui's own working directory
+func resolvePath(dir, file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(dir, file)
+}
+
+// moduleDir returns the source directory of a module, relative to the
+// Terraform working directory, from the manifest written by terraform init
+func moduleDir(workDir string, modules []string) (string, bool) {
+	if len(modules) == 0 {
+		return ".", true
+	}
+	data, err := os.ReadFile(filepath.Join(workDir, ".terraform", "modules", "modules.json"))
+	if err != nil {
+		return "", false
+	}
+	var manifest modulesManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", false
+	}
+	key := strings.Join(modules, ".")
+	for _, mod := range manifest.Modules {
+		if mod.Key == key {
+			return mod.Dir, true
+		}
+	}
+	return "", false
+}
+
+// resourceLocation finds the declaration of a resource address by looking for
+// a diagnostic marker that names the same resource in the module's source
+// directory. Resources in modules missing from the manifest are not found.
+func (m Model) resourceLocation(address string) (SourceLocation, bool) {
+	addr, ok := parseResourceAddress(address)
+	if !ok {
+		return SourceLocation{}, false
+	}
+	dir, ok := moduleDir(commandDir(m.command), addr.modules)
+	if !ok {
+		return SourceLocation{}, false
+	}
+	kind := "resource"
+	if addr.data {
+		kind = "data"
+	}
+
+	for _, diag := range m.diagnostics {
+		for _, detail := range diag.Detail {
+			if !detail.IsMarker {
+				continue
+			}
+			clean := stripANSI(detail.Content)
+			match := declarationPattern.FindStringSubmatch(clean)
+			if match == nil || match[1] != kind || match[2] != addr.resType || match[3] != addr.name {
+				continue
+			}
+			loc, ok := parseLocation(clean)
+			if ok && filepath.Clean(filepath.Dir(loc.File)) == filepath.Clean(dir) {
+				return loc, true
+			}
+		}
+	}
+	return SourceLocation{}, false
+}
+
+// openInEditor suspends the UI and opens the location under the cursor in $EDITOR
+func (m Model) openInEditor() tea.Cmd {
+	loc, ok := m.locationAt(m.cursor)
+	if !ok {
+		return nil
+	}
+	loc.File = resolvePath(commandDir(m.command), loc.File)
+	cmd := editorCommand(os.Getenv("EDITOR"), loc)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return editorFinishedMsg{err: err}
+	})
+}
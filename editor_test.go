@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEditorCommand(t *testing.T) {
+	loc := SourceLocation{File: "modules/vpc/main.tf", Line: 42}
+
+	cmd := editorCommand("nvim", loc)
+	if want := []string{"nvim", "+42", "modules/vpc/main.tf"}; !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("expected args %v, got %v", want, cmd.Args)
+	}
+
+	cmd = editorCommand("code --wait", loc)
+	if want := []string{"code", "--wait", "+42", "modules/vpc/main.tf"}; !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("expected args %v, got %v", want, cmd.Args)
+	}
+
+	cmd = editorCommand("", loc)
+	if cmd.Args[0] != defaultEditor {
+		t.Errorf("expected fallback editor %q, got %q", defaultEditor, cmd.Args[0])
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	testCases := []struct {
+		dir, file, want string
+	}{
+		{".", "main.tf", "main.tf"},
+		{"infra", "modules/vpc/main.tf", "infra/modules/vpc/main.tf"},
+		{"infra", "/src/main.tf", "/src/main.tf"},
+	}
+	for _, tc := range testCases {
+		if got := resolvePath(tc.dir, tc.file); got != tc.want {
+			t.Errorf("resolvePath(%q, %q) = %q, want %q", tc.dir, tc.file, got, tc.want)
+		}
+	}
+	dir := commandDir([]string{"terraform", "-chdir=infra", "plan"})
+	if got := resolvePath(dir, "main.tf"); got != filepath.Join("infra", "main.tf") {
+		t.Errorf("expected paths resolved against -chdir, got %q", got)
+	}
+}
+
+func TestLocationAt(t *testing.T) {
+	m := &Model{
+		tab:   TabLog,
+		width: 80,
+		diagnostics: []Diagnostic{
+			{
+				Severity: "error",
+				Summary:  "Unsupported argument",
+				Detail: []DiagnosticLine{
+					{Content: "  with aws_instance.web[0],"},
+					{Content: "  on compute.tf line 12, in resource \"aws_instance\" \"web\":", IsMarker: true},
+					{Content: "  12:   foo = 1"},
+				},
+				Expanded: true,
+			},
+		},
+		resources: []ResourceChange{
+			{Address: "aws_instance.web[0]", Action: "create"},
+			{Address: "aws_s3_bucket.logs", Action: "create"},
+		},
+	}
+	m.rebuildLines()
+
+	loc, ok := m.locationAt(0)
+	if !ok || loc.File != "compute.tf" || loc.Line != 12 {
+		t.Errorf("expected compute.tf:12 for diagnostic, got %+v (ok=%v)", loc, ok)
+	}
+
+	m.tab = TabPlan
+	m.rebuildLines()
+
+	loc, ok = m.locationAt(0)
+	if !ok || loc.File != "compute.tf" || loc.Line != 12 {
+		t.Errorf("expected compute.tf:12 for resource declaration, got %+v (ok=%v)", loc, ok)
+	}
+	if _, ok := m.locationAt(1); ok {
+		t.Error("expected no location for a resource without a diagnostic marker")
+	}
+}
+
+func TestParseResourceAddress(t *testing.T) {
+	cases := []struct {
+		address string
+		want    resourceAddress
+	}{
+		{"aws_instance.web[0]", resourceAddress{resType: "aws_instance", name: "web"}},
+		{`module.app["a.b"].aws_instance.web`, resourceAddress{modules: []string{"app"}, resType: "aws_instance", name: "web"}},
+		{`module.app["x"].module.db[1].data.aws_ami.ubuntu`, resourceAddress{modules: []string{"app", "db"}, data: true, resType: "aws_ami", name: "ubuntu"}},
+	}
+	for _, c := range cases {
+		got, ok := parseResourceAddress(c.address)
+		if !ok || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("parseResourceAddress(%q) = %+v, %v; want %+v", c.address, got, ok, c.want)
+		}
+	}
+	if _, ok := parseResourceAddress("module.app"); ok {
+		t.Error("expected a bare module address to be rejected")
+	}
+}
+
+func TestResourceLocationInModule(t *testing.T) {
+	dir := t.TempDir()
+	manifest := `{"Modules":[{"Key":"","Dir":"."},{"Key":"blue","Dir":"modules/blue"},{"Key":"green","Dir":"modules/green"}]}`
+	if err := os.MkdirAll(filepath.Join(dir, ".terraform", "modules"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".terraform", "modules", "modules.json"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	marker := func(file string, line int) Diagnostic {
+		return Diagnostic{Severity: "error", Summary: "Unsupported argument", Detail: []DiagnosticLine{
+			{Content: fmt.Sprintf(`  on %s line %d, in resource "aws_instance" "web":`, file, line), IsMarker: true},
+		}}
+	}
+	m := Model{
+		command:     []string{"terraform", "-chdir=" + dir, "plan"},
+		diagnostics: []Diagnostic{marker("modules/blue/main.tf", 3), marker("modules/green/main.tf", 7)},
+	}
+
+	loc, ok := m.resourceLocation(`module.green["a.b"].aws_instance.web`)
+	if !ok || loc.File != "modules/green/main.tf" || loc.Line != 7 {
+		t.Errorf("expected the declaration in the green module, got %+v (ok=%v)", loc, ok)
+	}
+	if _, ok := m.resourceLocation("aws_instance.web"); ok {
+		t.Error("expected no match for a root resource declared only in modules")
+	}
+	if _, ok := m.resourceLocation("module.red.aws_instance.web"); ok {
+		t.Error("expected no match for a module missing from the manifest")
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+const (
+	// exitChangesPresent is what plan -detailed-exitcode exits with when it
+	// succeeded and found changes
+	exitChangesPresent = 2
+	// exitQuitEarly is terraui's exit code when it quit before the wrapped
+	// command exited, as for a shell command stopped with Ctrl+C
+	exitQuitEarly = 130
+	// exitUsage is terraui's exit code for invalid flags (EX_USAGE), which
+	// Terraform never returns
+	exitUsage = 64
+)
+
+// usesDetailedExitCode reports whether the command was run with -detailed-exitcode
+func usesDetailedExitCode(command []string) bool {
+	for _, a := range command {
+		if a == "-detailed-exitcode" || a == "--detailed-exitcode" {
+			return true
+		}
+	}
+	return false
+}
+
+// commandFailed reports whether an exit code means the command failed.
+// With -detailed-exitcode, 2 means changes are present, not an error.
+func commandFailed(command []string, code int) bool {
+	return code != 0 && !(code == exitChangesPresent && usesDetailedExitCode(command))
+}
+
+// changesPresent reports whether a -detailed-exitcode plan exited with changes
+func (m Model) changesPresent() bool {
+	return m.exitCode == exitChangesPresent && usesDetailedExitCode(m.command)
+}
+
+// processExitCode returns the code terraui exits with: the wrapped command's,
+// so scripts can tell what happened
+func (m Model) processExitCode() int {
+	if len(m.command) == 0 {
+		return 0
+	}
+	if m.exitCode < 0 {
+		return exitQuitEarly
+	}
+	return m.exitCode
+}
+
+// signalExitCode returns the shell's exit code for a process stopped by sig:
+// 128 plus the signal number, e.g. 130 for SIGINT and 143 for SIGTERM
+func signalExitCode(sig os.Signal) int {
+	if s, ok := sig.(syscall.Signal); ok {
+		return 128 + int(s)
+	}
+	return exitQuitEarly
+}
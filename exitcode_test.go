@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestCommandFailed(t *testing.T) {
+	plan := []string{"terraform", "plan"}
+	detailed := []string{"terraform", "plan", "-detailed-exitcode"}
+	cases := []struct {
+		command []string
+		code    int
+		want    bool
+	}{
+		{plan, 0, false},
+		{plan, 1, true},
+		{plan, 2, true},
+		{detailed, 0, false},
+		{detailed, 1, true},
+		{detailed, 2, false},
+	}
+	for _, c := range cases {
+		if got := commandFailed(c.command, c.code); got != c.want {
+			t.Errorf("commandFailed(%v, %d) = %v, want %v", c.command, c.code, got, c.want)
+		}
+	}
+}
+
+func TestDetailedExitCodeShowsChangesPresent(t *testing.T) {
+	command := []string{"terraform", "plan", "-detailed-exitcode"}
+	m := Model{width: 120, height: 30, ready: true, command: command, exitCode: -1}
+	updated, _ := m.Update(exitCodeMsg{exitCode: 2, hasError: commandFailed(command, 2)})
+	m = updated.(Model)
+
+	header := stripANSI(m.renderHeader())
+	if !strings.Contains(header, "CHANGES PRESENT") || strings.Contains(header, "ERROR") {
+		t.Errorf("expected changes present rather than an error, got %q", header)
+	}
+	if got := m.processExitCode(); got != 2 {
+		t.Errorf("expected exit code 2 passed through, got %d", got)
+	}
+}
+
+func TestProcessExitCode(t *testing.T) {
+	cases := []struct {
+		name    string
+		command []string
+		code    int
+		want    int
+	}{
+		{"pipe mode", nil, 0, 0},
+		{"success", []string{"terraform", "apply"}, 0, 0},
+		{"failure", []string{"terraform", "apply"}, 1, 1},
+		{"quit before exit", []string{"terraform", "apply"}, -1, exitQuitEarly},
+	}
+	for _, c := range cases {
+		m := Model{command: c.command, exitCode: c.code}
+		if got := m.processExitCode(); got != c.want {
+			t.Errorf("%s: expected %d, got %d", c.name, c.want, got)
+		}
+	}
+}
+
+func TestSignalExitCode(t *testing.T) {
+	if got := signalExitCode(syscall.SIGINT); got != 130 {
+		t.Errorf("expected 130 for SIGINT, got %d", got)
+	}
+	if got := signalExitCode(syscall.SIGTERM); got != 143 {
+		t.Errorf("expected 143 for SIGTERM, got %d", got)
+	}
+}
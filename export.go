@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// exportText returns the session's output as plain text: the log with each
+// diagnostic where it occurred, without colors. Secrets are already redacted.
+func (m Model) exportText() string {
+	var b strings.Builder
+	next := 0
+	writeDiagnosticsBefore := func(pos int) {
+		for ; next < len(m.diagnostics) && m.diagnostics[next].LogPos <= pos; next++ {
+			d := m.diagnostics[next]
+			label := "Warning"
+			if d.Severity == "error" {
+				label = "Error"
+			}
+			b.WriteString(label + ": " + stripANSI(d.Summary) + "\n")
+			for _, line := range d.Detail {
+				b.WriteString(stripANSI(line.Content) + "\n")
+			}
+			b.WriteString("\n")
+		}
+	}
+	for i, line := range m.logs {
+		writeDiagnosticsBefore(i)
+		b.WriteString(stripANSI(line) + "\n")
+	}
+	writeDiagnosticsBefore(len(m.logs))
+	return b.String()
+}
+
+// exportOutput writes exportText to path, or to stdout for "-"
+func (m Model) exportOutput(path string) error {
+	if path == "-" {
+		_, err := os.Stdout.WriteString(m.exportText())
+		return err
+	}
+	return os.WriteFile(path, []byte(m.exportText()), 0o644)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportOutput(t *testing.T) {
+	m := Model{
+		logs: []string{"\x1b[1mInitializing...\x1b[0m", "Planning..."},
+		diagnostics: []Diagnostic{{
+			Severity: "error",
+			Summary:  "Invalid reference",
+			Detail:   []DiagnosticLine{{Content: "  on main.tf line 3:"}},
+			LogPos:   1,
+		}},
+	}
+	path := filepath.Join(t.TempDir(), "out.txt")
+	if err := m.exportOutput(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Initializing...\nError: Invalid reference\n  on main.tf line 3:\n\nPlanning...\n"
+	if string(data) != want {
+		t.Errorf("expected plain text with the diagnostic in place, got %q", data)
+	}
+}
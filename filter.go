@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// actionOrder lists actions in the order they appear in summaries and filters
+var actionOrder = []string{"create", "update", "destroy", "replace", "import"}
+
+// dangerousActions is the "dangerous only" filter preset
+var dangerousActions = []string{"destroy", "replace"}
+
+// actionFilterKeys maps the key pressed after "f" to the action it toggles
+var actionFilterKeys = map[string]string{
+	"c": "create",
+	"u": "update",
+	"d": "destroy",
+	"r": "replace",
+	"i": "import",
+}
+
+// resourceVisible reports whether a resource passes the active filters
+func (m Model) resourceVisible(rc ResourceChange) bool {
+	if len(m.actionFilter) > 0 && !m.actionFilter[rc.Action] {
+		return false
+	}
+	return m.addrFilter.allows(rc.Address)
+}
+
+// visibleResources returns the resources that pass the filters
+func (m Model) visibleResources() []ResourceChange {
+	var out []ResourceChange
+	for _, rc := range m.resources {
+		if m.resourceVisible(rc) {
+			out = append(out, rc)
+		}
+	}
+	return out
+}
+
+// visibleResourceCount returns the number of resources that pass the filters
+func (m Model) visibleResourceCount() int {
+	n := 0
+	for _, rc := range m.resources {
+		if m.resourceVisible(rc) {
+			n++
+		}
+	}
+	return n
+}
+
+// toggleActionFilter adds or removes an action from the filter
+func (m *Model) toggleActionFilter(action string) {
+	if m.actionFilter == nil {
+		m.actionFilter = make(map[string]bool)
+	}
+	if m.actionFilter[action] {
+		delete(m.actionFilter, action)
+	} else {
+		m.actionFilter[action] = true
+	}
+}
+
+// isDangerousFilter reports whether the filter is exactly the "dangerous only" preset
+func (m Model) isDangerousFilter() bool {
+	if len(m.actionFilter) != len(dangerousActions) {
+		return false
+	}
+	for _, a := range dangerousActions {
+		if !m.actionFilter[a] {
+			return false
+		}
+	}
+	return true
+}
+
+// handleFilterKey applies the second key of an "f" chord: an action key
+// toggles that action, "!" toggles the dangerous-only preset and "a" clears.
+func (m *Model) handleFilterKey(key string) {
+	switch key {
+	case "!":
+		if m.isDangerousFilter() {
+			m.actionFilter = nil
+		} else {
+			m.actionFilter = make(map[string]bool)
+			for _, a := range dangerousActions {
+				m.actionFilter[a] = true
+			}
+		}
+	case "a":
+		m.actionFilter = nil
+	default:
+		action, ok := actionFilterKeys[key]
+		if !ok {
+			return
+		}
+		m.toggleActionFilter(action)
+	}
+	m.rebuildLines()
+	m.clampCursor()
+	m.clampOffset()
+}
+
+// filterLabel describes the active filters for the header, or "" if none
+func (m Model) filterLabel() string {
+	var parts []string
+	if m.isDangerousFilter() {
+		parts = append(parts, "dangerous only")
+	} else {
+		for _, a := range actionOrder {
+			if m.actionFilter[a] {
+				parts = append(parts, getSymbol(a)+a)
+			}
+		}
+	}
+	if !m.addrFilter.empty() {
+		parts = append(parts, m.addrFilter.String())
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "filter: " + strings.Join(parts, " ")
+}
+
+// filterCountLabel returns "showing N of M" when filters hide resources
+func (m Model) filterCountLabel() string {
+	visible := m.visibleResourceCount()
+	if visible == len(m.resources) {
+		return ""
+	}
+	return fmt.Sprintf("showing %d of %d", visible, len(m.resources))
+}
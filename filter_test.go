@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// filterResources has one resource or more for each action
+var filterResources = []ResourceChange{
+	planResource("aws_vpc.main", "create"),
+	planResource("aws_instance.web", "update"),
+	planResource("aws_db_instance.old", "destroy"),
+	planResource("aws_launch_template.app", "replace"),
+	planResource("aws_s3_bucket.logs", "create"),
+}
+
+func visibleAddresses(m Model) []string {
+	var out []string
+	for _, l := range m.lines {
+		if l.Type == LineTypeResource {
+			out = append(out, m.resources[l.ResourceIdx].Address)
+		}
+	}
+	return out
+}
+
+func TestActionFilterToggles(t *testing.T) {
+	m := planModel(120, 0, filterResources...)
+
+	m = typeKeys(m, "f", "c")
+	if got := visibleAddresses(m); len(got) != 2 || got[0] != "aws_vpc.main" || got[1] != "aws_s3_bucket.logs" {
+		t.Errorf("expected only creates, got %v", got)
+	}
+
+	m = typeKeys(m, "f", "u")
+	if got := visibleAddresses(m); len(got) != 3 {
+		t.Errorf("expected creates and updates, got %v", got)
+	}
+
+	m = typeKeys(m, "f", "c", "f", "u")
+	if got := visibleAddresses(m); len(got) != 5 {
+		t.Errorf("expected toggling off all actions to show everything, got %v", got)
+	}
+}
+
+func TestActionFilterDangerousPreset(t *testing.T) {
+	m := planModel(120, 0, filterResources...)
+
+	m = typeKeys(m, "f", "!")
+	got := visibleAddresses(m)
+	if len(got) != 2 || got[0] != "aws_db_instance.old" || got[1] != "aws_launch_template.app" {
+		t.Errorf("expected destroys and replaces only, got %v", got)
+	}
+	if header := m.renderHeader(); !strings.Contains(header, "filter: dangerous only") {
+		t.Errorf("expected active filter in header, got %q", header)
+	}
+	if footer := m.renderFooter(); !strings.Contains(footer, "showing 2 of 5") {
+		t.Errorf("expected filtered count in footer, got %q", footer)
+	}
+
+	m = typeKeys(m, "f", "a")
+	if got := visibleAddresses(m); len(got) != 5 {
+		t.Errorf("expected f a to clear the filter, got %v", got)
+	}
+	if footer := m.renderFooter(); strings.Contains(footer, "showing") {
+		t.Errorf("expected no filtered count without a filter, got %q", footer)
+	}
+}
+
+func TestActionFilterDoesNotConsumeNextKey(t *testing.T) {
+	m := planModel(120, 0, filterResources...)
+	m = typeKeys(m, "f", "x", "j")
+	if m.pendingKey != "" {
+		t.Error("expected an unknown filter key to end the chord")
+	}
+	if m.cursor != 1 {
+		t.Errorf("expected navigation to work after the chord, got cursor %d", m.cursor)
+	}
+}
+
+func TestActionFilterKeyOnlyInPlanTab(t *testing.T) {
+	m := planModel(120, 0, filterResources...)
+	m.logs = []string{"one", "two", "three"}
+	m = typeKeys(m, "3", "f", "j")
+	if m.pendingKey != "" {
+		t.Error("expected f to start no chord outside the Plan tab")
+	}
+	if m.cursor != 1 {
+		t.Errorf("expected the key after f to move the cursor in the Log tab, got %d", m.cursor)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// guardListLimit is the number of destructive resources listed in the guard dialog
+const guardListLimit = 10
+
+// approvalContextLines is how many log lines before the prompt are searched
+// for Terraform's approval question.
+const approvalContextLines = 6
+
+var (
+	// approvalQuestionPattern matches the question Terraform asks before an
+	// apply or destroy, e.g. `Do you want to perform these actions in workspace "prod"?`
+	approvalQuestionPattern = regexp.MustCompile(`^\s*Do you (?:really )?want to (?:perform these actions|destroy all resources)(?: in workspace "([^"]+)")?\?`)
+)
+
+// approvalQuestion returns the approval question shown above the active
+// prompt, if the prompt is Terraform's apply/destroy confirmation.
+func (m Model) approvalQuestion() (string, bool) {
+	if m.prompt == "" {
+		return "", false
+	}
+	for i := len(m.logs) - 1; i >= 0 && i >= len(m.logs)-approvalContextLines; i-- {
+		clean := stripANSI(m.logs[i])
+		if approvalQuestionPattern.MatchString(clean) {
+			return strings.TrimSpace(clean), true
+		}
+	}
+	return "", false
+}
+
+// currentWorkspace returns the Terraform workspace of the wrapped command:
+// the one named in the approval question, $TF_WORKSPACE, the selected
+// workspace in .terraform/environment of its -chdir directory, or "default".
+func (m Model) currentWorkspace() string {
+	if question, ok := m.approvalQuestion(); ok {
+		if match := approvalQuestionPattern.FindStringSubmatch(question); match[1] != "" {
+			return match[1]
+		}
+	}
+	if ws := os.Getenv("TF_WORKSPACE"); ws != "" {
+		return ws
+	}
+	if data, err := os.ReadFile(filepath.Join(commandDir(m.command), ".terraform", "environment")); err == nil {
+		if ws := strings.TrimSpace(string(data)); ws != "" {
+			return ws
+		}
+	}
+	return "default"
+}
+
+// destructiveResources returns the indices of resources that will be
+// destroyed or replaced.
+func (m Model) destructiveResources() []int {
+	var idx []int
+	for i, rc := range m.resources {
+		if rc.Action == "destroy" || rc.Action == "replace" {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// guardEnforced reports whether approvals in the workspace must be guarded.
+// With no guard_workspaces configured every workspace is guarded.
+func (m Model) guardEnforced(workspace string) bool {
+	if len(m.config.GuardWorkspaces) == 0 {
+		return true
+	}
+	for _, pattern := range m.config.GuardWorkspaces {
+		if ok, _ := path.Match(pattern, workspace); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// needsGuard reports whether submitting answer at the current prompt approves
+// a plan with destroys or replaces, and so must be confirmed first.
+func (m Model) needsGuard(answer string) bool {
+	if strings.TrimSpace(answer) != "yes" {
+		return false
+	}
+	if _, ok := m.approvalQuestion(); !ok {
+		return false
+	}
+	return len(m.destructiveResources()) > 0 && m.guardEnforced(m.currentWorkspace())
+}
+
+// confirmDestructiveApply opens a dialog listing the destroys and replaces.
+// "yes" is only written to the PTY once the destroy count or the workspace
+// name has been typed.
+func (m *Model) confirmDestructiveApply() {
+	destructive := m.destructiveResources()
+	workspace := m.currentWorkspace()
+
+	lines := []string{fmt.Sprintf("This apply destroys or replaces %d resource(s) in workspace %q:", len(destructive), workspace)}
+	for i, idx := range destructive {
+		if i == guardListLimit {
+			lines = append(lines, fmt.Sprintf("  … and %d more", len(destructive)-guardListLimit))
+			break
+		}
+		rc := m.resources[idx]
+		lines = append(lines, fmt.Sprintf("  %s %s", getSymbol(rc.Action), rc.Address))
+	}
+	lines = append(lines, fmt.Sprintf("Type %d or %s to approve:", len(destructive), workspace))
+
+	m.dialog = &confirmDialog{
+		title:  "DESTRUCTIVE APPLY",
+		lines:  lines,
+		expect: []string{strconv.Itoa(len(destructive)), workspace},
+		onConfirm: func(m *Model) tea.Cmd {
+			m.submitInput("yes")
+			return nil
+		},
+	}
+}
+
+// approvalKeysActive reports whether y/n answer the approval question. They
+// are off while a search is active, where n and N move between matches.
+func (m Model) approvalKeysActive() bool {
+	if m.ptyFile == nil || m.search != nil {
+		return false
+	}
+	_, ok := m.approvalQuestion()
+	return ok
+}
+
+// handleApprovalKey answers Terraform's apply/destroy approval question with
+// y (approve) or n (reject). Keys are only taken when the question above the
+// prompt has been identified, never at variable prompts. Approvals still go
+// through the destructive apply guard.
+func (m *Model) handleApprovalKey(key string) bool {
+	if (key != "y" && key != "n") || !m.approvalKeysActive() {
+		return false
+	}
+
+	answer := "no"
+	if key == "y" {
+		answer = "yes"
+	}
+	if m.needsGuard(answer) {
+		m.confirmDestructiveApply()
+		m.logs = append(m.logs, fmt.Sprintf("Approval key %q pressed: confirmation required", key))
+		m.needsSync = true
+		return true
+	}
+	m.logs = append(m.logs, fmt.Sprintf("Approval key %q pressed: answered %q", key, answer))
+	m.submitInput(answer)
+	return true
+}
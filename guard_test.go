@@ -0,0 +1,200 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// guardModel returns a model waiting at the apply approval prompt, writing
+// its answers to a temp file standing in for the PTY.
+func guardModel(t *testing.T, actions ...string) (Model, *os.File) {
+	t.Helper()
+	f, err := os.Create(filepath.Join(t.TempDir(), "pty"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	m := Model{width: 100, height: 30, ready: true, ptyFile: f, prompt: "Enter a value:"}
+	for i, action := range actions {
+		m.resources = append(m.resources, ResourceChange{Address: "null_resource.r" + string(rune('a'+i)), Action: action})
+	}
+	m.logs = []string{
+		`Do you want to perform these actions in workspace "prod"?`,
+		"  Terraform will perform the actions described above.",
+		"  Only 'yes' will be accepted to approve.",
+		"",
+	}
+	m.rebuildLines()
+	return m, f
+}
+
+func ptyWritten(t *testing.T, f *os.File) string {
+	t.Helper()
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestDestructiveApplyRequiresConfirmation(t *testing.T) {
+	m, f := guardModel(t, "create", "destroy", "replace")
+	m = typeKeys(m, "i", "yes", "enter")
+
+	if m.dialog == nil {
+		t.Fatal("expected approving destroys to open the guard dialog")
+	}
+	if got := ptyWritten(t, f); got != "" {
+		t.Fatalf("expected nothing written before confirmation, got %q", got)
+	}
+	body := strings.Join(m.dialog.lines, "\n")
+	if !strings.Contains(body, "null_resource.rb") || !strings.Contains(body, "null_resource.rc") || strings.Contains(body, "null_resource.ra") {
+		t.Errorf("expected only the destroyed and replaced resources listed, got:\n%s", body)
+	}
+
+	m = typeKeys(m, "yes", "enter")
+	if m.dialog == nil || !m.dialog.mismatch {
+		t.Fatal("expected a plain yes to be rejected by the guard")
+	}
+
+	m = typeKeys(m, "2", "enter")
+	if m.dialog != nil {
+		t.Fatal("expected the destroy count to confirm")
+	}
+	if got := ptyWritten(t, f); got != "yes\n" {
+		t.Errorf("expected yes written after confirmation, got %q", got)
+	}
+	if m.prompt != "" || m.tab != TabLog {
+		t.Error("expected the prompt cleared and the Log tab shown")
+	}
+}
+
+func TestDestructiveApplyAcceptsWorkspaceName(t *testing.T) {
+	m, f := guardModel(t, "destroy")
+	m = typeKeys(m, "i", "yes", "enter", "prod", "enter")
+	if got := ptyWritten(t, f); got != "yes\n" {
+		t.Errorf("expected the workspace name to confirm, got %q", got)
+	}
+}
+
+func TestGuardCancel(t *testing.T) {
+	m, f := guardModel(t, "destroy")
+	m = typeKeys(m, "i", "yes", "enter", "esc")
+	if m.dialog != nil || ptyWritten(t, f) != "" {
+		t.Error("expected Esc to close the dialog without answering")
+	}
+	if m.prompt == "" {
+		t.Error("expected the prompt to stay pending after cancelling")
+	}
+}
+
+func TestGuardSkipped(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(m *Model)
+		answer string
+	}{
+		{"no destroys", func(m *Model) { m.resources[0].Action = "create" }, "yes"},
+		{"answer is not yes", func(m *Model) {}, "no"},
+		{"variable prompt", func(m *Model) { m.logs = []string{"var.region", "  Enter a region", ""} }, "yes"},
+		{"workspace not guarded", func(m *Model) { m.config.GuardWorkspaces = []string{"prod-*"} }, "yes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, f := guardModel(t, "destroy")
+			tt.setup(&m)
+			m = typeKeys(m, "i", tt.answer, "enter")
+			if m.dialog != nil {
+				t.Fatal("expected no guard dialog")
+			}
+			if got := ptyWritten(t, f); got != tt.answer+"\n" {
+				t.Errorf("expected the answer written directly, got %q", got)
+			}
+		})
+	}
+}
+
+func TestGuardWorkspacePatterns(t *testing.T) {
+	m := Model{config: Config{GuardWorkspaces: []string{"prod", "staging-*"}}}
+	for ws, want := range map[string]bool{"prod": true, "staging-eu": true, "dev": false} {
+		if got := m.guardEnforced(ws); got != want {
+			t.Errorf("guardEnforced(%q) = %v, want %v", ws, got, want)
+		}
+	}
+}
+
+func TestApprovalKeys(t *testing.T) {
+	m, f := guardModel(t, "create")
+	m = typeKeys(m, "y")
+	if got := ptyWritten(t, f); got != "yes\n" {
+		t.Errorf("expected y to approve, got %q", got)
+	}
+	if last := m.logs[len(m.logs)-1]; !strings.Contains(last, `"y"`) {
+		t.Errorf("expected the key recorded in the log, got %q", last)
+	}
+
+	m, f = guardModel(t, "create")
+	m = typeKeys(m, "n")
+	if got := ptyWritten(t, f); got != "no\n" {
+		t.Errorf("expected n to reject, got %q", got)
+	}
+	if m.prompt != "" {
+		t.Error("expected the prompt cleared after answering")
+	}
+}
+
+func TestApprovalKeysGuarded(t *testing.T) {
+	m, f := guardModel(t, "destroy")
+	m = typeKeys(m, "y")
+	if m.dialog == nil || ptyWritten(t, f) != "" {
+		t.Fatal("expected y on a destructive plan to open the guard dialog")
+	}
+	m = typeKeys(m, "1", "enter")
+	if got := ptyWritten(t, f); got != "yes\n" {
+		t.Errorf("expected yes after confirmation, got %q", got)
+	}
+}
+
+func TestApprovalKeysIgnoredAtVariablePrompt(t *testing.T) {
+	m, f := guardModel(t, "create")
+	m.logs = []string{"var.enabled", "  Set to y to enable", ""}
+	m = typeKeys(m, "y", "n")
+	if got := ptyWritten(t, f); got != "" {
+		t.Errorf("expected y/n ignored at a variable prompt, got %q", got)
+	}
+	if m.prompt == "" {
+		t.Error("expected the variable prompt to stay pending")
+	}
+}
+
+func TestCurrentWorkspaceUsesChdir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".terraform"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".terraform", "environment"), []byte("staging\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TF_WORKSPACE", "")
+
+	m := Model{command: []string{"terraform", "-chdir=" + dir, "apply"}}
+	if got := m.currentWorkspace(); got != "staging" {
+		t.Errorf("expected the workspace selected in the -chdir directory, got %q", got)
+	}
+}
+
+func TestApprovalKeysYieldToSearch(t *testing.T) {
+	m, f := guardModel(t, "create", "create")
+	m = typeKeys(m, "/", "null_resource", "enter")
+	from := m.cursor
+	m = typeKeys(m, "n")
+	if got := ptyWritten(t, f); got != "" {
+		t.Fatalf("expected n to find the next match, not reject the apply, got %q", got)
+	}
+	if m.cursor == from || m.prompt == "" {
+		t.Errorf("expected the cursor to move with the prompt still pending, cursor %d -> %d", from, m.cursor)
+	}
+}
@@ -31,3 +31,51 @@ func collectStreamMsgs(m *Model, input string) (diagnostics []*Diagnostic, logs
 	}
 	return
 }
+
+// streamIntoModel runs input through the stream reader and applies every
+// message to the model in arrival order, as the UI loop would.
+func streamIntoModel(m Model, input string) Model {
+	m.streamChan = make(chan StreamMsg, streamBufferSize)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The reader gets its own copy, as the loop below replaces m
+	reader := m
+	go reader.readInputStream(ctx, strings.NewReader(input))
+
+	for {
+		msg, ok := <-m.streamChan
+		if !ok {
+			break
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+		if msg.Done {
+			break
+		}
+	}
+	m.rebuildLines()
+	return m
+}
+
+// planActionTexts are the header texts Terraform prints for each action
+var planActionTexts = map[string]string{
+	"create":  "will be created",
+	"update":  "will be updated in-place",
+	"destroy": "will be destroyed",
+	"replace": "must be replaced",
+}
+
+// planResource returns a collapsed resource change with Terraform's header
+// text for its action
+func planResource(address, action string, attrs ...string) ResourceChange {
+	return ResourceChange{Address: address, Action: action, ActionText: planActionTexts[action], Attributes: attrs}
+}
+
+// planModel returns a ready model showing a copy of resources in the Plan tab,
+// so tests can share a fixture and still expand or mark resources
+func planModel(width, height int, resources ...ResourceChange) Model {
+	m := Model{width: width, height: height, ready: true, resources: append([]ResourceChange(nil), resources...)}
+	m.rebuildLines()
+	return m
+}
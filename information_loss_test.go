@@ -1375,3 +1375,89 @@ Plan: 2 to add, 0 to change, 0 to destroy.
 		}
 	}
 }
+
+func TestLogLayoutsPreserveAllContent(t *testing.T) {
+	// Both Log tab layouts must render every log line and every diagnostic line
+	input := `Standard Terraform plan output here
+╷
+│ Error: Standard GCP error
+│ 
+│   with google_compute_instance.test
+│ 
+╵
+Some random log line
+[ERROR] Custom provider error without standard format
+╷
+│ Warning: Another standard warning
+│ 
+│ Deprecated argument used here
+╵
+Final log message
+`
+	for _, chronological := range []bool{false, true} {
+		m := streamIntoModel(Model{width: 200}, input)
+		m = typeKeys(m, "3", "e")
+		if chronological {
+			m = typeKeys(m, "t")
+		}
+
+		var rendered []string
+		for _, l := range m.lines {
+			rendered = append(rendered, stripANSI(l.Content))
+		}
+		all := strings.Join(rendered, "\n")
+
+		for _, log := range m.logs {
+			if !strings.Contains(all, stripANSI(log)) {
+				t.Errorf("chronological=%v: log line lost: %q", chronological, log)
+			}
+		}
+		for _, d := range m.diagnostics {
+			if !strings.Contains(all, d.Summary) {
+				t.Errorf("chronological=%v: diagnostic summary lost: %q", chronological, d.Summary)
+			}
+			for _, detail := range d.Detail {
+				if !strings.Contains(all, stripANSI(detail.Content)) {
+					t.Errorf("chronological=%v: diagnostic detail lost: %q", chronological, detail.Content)
+				}
+			}
+		}
+	}
+}
+
+func TestRepeatedSummariesPreserveEveryDetail(t *testing.T) {
+	// Diagnostics grouped by summary must still show each occurrence's own detail
+	input := `╷
+│ Error: Invalid value
+│ 
+│   with aws_instance.web[0],
+│   on main.tf line 4, in resource "aws_instance" "web":
+│ 
+│ The instance type "t2.nano" is not available in this zone.
+╵
+╷
+│ Error: Invalid value
+│ 
+│   with aws_instance.web[1],
+│   on main.tf line 4, in resource "aws_instance" "web":
+│ 
+│ The subnet "subnet-123" does not exist.
+╵
+`
+	for _, keys := range [][]string{{"2", "e"}, {"3", "e"}, {"3", "e", "t"}} {
+		m := streamIntoModel(Model{width: 200}, input)
+		m = typeKeys(m, keys...)
+
+		var rendered []string
+		for _, l := range m.lines {
+			rendered = append(rendered, stripANSI(l.Content))
+		}
+		all := strings.Join(rendered, "\n")
+
+		for _, line := range meaningfulLines(input) {
+			if !strings.Contains(all, line) {
+				t.Errorf("keys %v: ZERO-LOSS VIOLATION: line not found in view: %q", keys, line)
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// interruptChar is what a terminal sends for Ctrl+C; through the PTY it
+// becomes SIGINT for the wrapped command
+const interruptChar = "\x03"
+
+// applySubcommands are the Terraform subcommands that change infrastructure
+var applySubcommands = map[string]bool{"apply": true, "destroy": true}
+
+// commandRunning reports whether a wrapped command is still running
+func (m Model) commandRunning() bool {
+	return m.ptyFile != nil && m.exitCode < 0
+}
+
+// applyRunning reports whether the running command is an apply or destroy
+func (m Model) applyRunning() bool {
+	if !m.commandRunning() || len(m.command) == 0 {
+		return false
+	}
+	for _, a := range m.command[1:] {
+		if applySubcommands[a] {
+			return true
+		}
+	}
+	return false
+}
+
+// interrupt handles Ctrl+C. The first press asks the running command to stop
+// gracefully; pressing again asks for confirmation before killing it.
+// Without a running command it quits.
+func (m *Model) interrupt() tea.Cmd {
+	if !m.commandRunning() {
+		return m.quit()
+	}
+	if m.stopping {
+		m.confirmForceStop()
+		return nil
+	}
+	if _, err := m.ptyFile.Write([]byte(interruptChar)); err != nil {
+		m.logs = append(m.logs, fmt.Sprintf("Failed to send interrupt: %v", err))
+		m.needsSync = true
+		return nil
+	}
+	m.stopping = true
+	return nil
+}
+
+// requestQuit handles q. Quitting in the middle of an apply asks first, as
+// it can leave the state locked or resources half-created.
+func (m *Model) requestQuit() tea.Cmd {
+	if !m.applyRunning() {
+		return m.quit()
+	}
+	m.dialog = &confirmDialog{
+		title: "QUIT DURING APPLY",
+		lines: []string{
+			strings.Join(m.command, " ") + " is still running.",
+			"Quitting stops it and may leave the state locked or resources half-created.",
+			"Press Ctrl+C instead to let it stop gracefully.",
+			"Type quit to confirm:",
+		},
+		expect: []string{"quit"},
+		onConfirm: func(m *Model) tea.Cmd {
+			return m.quit()
+		},
+	}
+	return nil
+}
+
+// confirmForceStop opens a dialog that kills the command once confirmed
+func (m *Model) confirmForceStop() {
+	m.dialog = &confirmDialog{
+		title: "FORCE STOP",
+		lines: []string{
+			strings.Join(m.command, " ") + " has not stopped after the interrupt.",
+			"Killing it may leave the state locked or resources half-created.",
+			"Type kill to confirm:",
+		},
+		expect: []string{"kill"},
+		onConfirm: func(m *Model) tea.Cmd {
+			if m.killFunc != nil {
+				m.killFunc()
+			}
+			return m.quit()
+		},
+	}
+}
+
+// quit ends the session
+func (m *Model) quit() tea.Cmd {
+	if m.cancelFunc != nil {
+		m.cancelFunc()
+	}
+	return tea.Quit
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runningApply returns a model wrapping a running apply, counting kills
+func runningApply(t *testing.T) (Model, *int) {
+	m, _ := guardModel(t)
+	m.command = []string{"terraform", "apply"}
+	m.exitCode = -1
+	kills := 0
+	m.killFunc = func() { kills++ }
+	return m, &kills
+}
+
+func pressCtrlC(m Model) (Model, tea.Cmd) {
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	return updated.(Model), cmd
+}
+
+func TestInterruptSendsSIGINTThroughPTY(t *testing.T) {
+	m, kills := runningApply(t)
+	f := m.ptyFile
+
+	m, cmd := pressCtrlC(m)
+	if cmd != nil {
+		t.Fatal("expected the first interrupt not to quit")
+	}
+	if got := ptyWritten(t, f); got != "\x03" {
+		t.Errorf("expected Ctrl+C written to the PTY, got %q", got)
+	}
+	if header := stripANSI(m.renderHeader()); !strings.Contains(header, "Stopping… (press again to force)") {
+		t.Errorf("expected the stopping state in the header, got %q", header)
+	}
+
+	m, _ = pressCtrlC(m)
+	if m.dialog == nil || m.dialog.title != "FORCE STOP" {
+		t.Fatal("expected a second interrupt to ask before killing")
+	}
+	if *kills != 0 {
+		t.Fatal("expected no kill before confirmation")
+	}
+	m = typeKeys(m, "kill", "enter")
+	if *kills != 1 {
+		t.Errorf("expected the command killed after confirmation, got %d kills", *kills)
+	}
+	if got := ptyWritten(t, f); got != "\x03" {
+		t.Errorf("expected only one interrupt written, got %q", got)
+	}
+}
+
+func TestInterruptClearedOnExit(t *testing.T) {
+	m, _ := runningApply(t)
+	m, _ = pressCtrlC(m)
+	updated, _ := m.Update(exitCodeMsg{exitCode: 1, hasError: true})
+	m = updated.(Model)
+	if m.stopping {
+		t.Error("expected the stopping state cleared when the command exits")
+	}
+	if _, cmd := pressCtrlC(m); cmd == nil {
+		t.Error("expected Ctrl+C to quit once the command has exited")
+	}
+}
+
+func TestQuitDuringApplyAsksFirst(t *testing.T) {
+	m, kills := runningApply(t)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	m = updated.(Model)
+	if cmd != nil || m.dialog == nil {
+		t.Fatal("expected q during an apply to ask for confirmation")
+	}
+	m = typeKeys(m, "esc")
+	if m.dialog != nil {
+		t.Fatal("expected Esc to cancel quitting")
+	}
+
+	m = typeKeys(m, "q", "quit")
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Error("expected confirming to quit")
+	}
+	if *kills != 0 {
+		t.Error("expected quitting to leave stopping the command to cleanup")
+	}
+}
+
+func TestQuitWithoutApplyIsImmediate(t *testing.T) {
+	m, _ := runningApply(t)
+	m.command = []string{"terraform", "plan"}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd == nil {
+		t.Error("expected q to quit while a plan runs")
+	}
+	m = Model{width: 80, height: 24, ready: true}
+	if _, cmd := pressCtrlC(m); cmd == nil {
+		t.Error("expected Ctrl+C to quit in pipe mode")
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// lineEditor is the input-mode text buffer: a rune-indexed cursor, readline
+// style editing keys and a history of earlier answers.
+type lineEditor struct {
+	text    []rune
+	pos     int      // Cursor position in runes, 0..len(text)
+	history []string // Submitted answers, oldest first
+	histPos int      // Entry being shown; len(history) means the draft
+	draft   string   // Text typed before browsing the history
+}
+
+// String returns the current text
+func (e *lineEditor) String() string {
+	return string(e.text)
+}
+
+// set replaces the text and puts the cursor at the end
+func (e *lineEditor) set(s string) {
+	e.text = []rune(s)
+	e.pos = len(e.text)
+}
+
+// insert adds typed or pasted text at the cursor. Control characters such
+// as pasted newlines are dropped so a paste can't submit the answer.
+func (e *lineEditor) insert(s string) {
+	var runes []rune
+	for _, r := range s {
+		if !unicode.IsControl(r) {
+			runes = append(runes, r)
+		}
+	}
+	text := make([]rune, 0, len(e.text)+len(runes))
+	text = append(text, e.text[:e.pos]...)
+	text = append(text, runes...)
+	text = append(text, e.text[e.pos:]...)
+	e.text = text
+	e.pos += len(runes)
+}
+
+// deleteRange removes text[from:to] and leaves the cursor at from
+func (e *lineEditor) deleteRange(from, to int) {
+	if from >= to {
+		return
+	}
+	e.text = append(e.text[:from:from], e.text[to:]...)
+	e.pos = from
+}
+
+// wordStart returns the start of the word before the cursor, skipping any
+// spaces directly before it
+func (e *lineEditor) wordStart() int {
+	i := e.pos
+	for i > 0 && unicode.IsSpace(e.text[i-1]) {
+		i--
+	}
+	for i > 0 && !unicode.IsSpace(e.text[i-1]) {
+		i--
+	}
+	return i
+}
+
+// historyPrev shows the previous answer, saving the draft on first use
+func (e *lineEditor) historyPrev() {
+	if e.histPos == 0 || len(e.history) == 0 {
+		return
+	}
+	if e.histPos >= len(e.history) {
+		e.histPos = len(e.history)
+		e.draft = e.String()
+	}
+	e.histPos--
+	e.set(e.history[e.histPos])
+}
+
+// historyNext shows the next answer, or the draft after the newest one
+func (e *lineEditor) historyNext() {
+	if e.histPos >= len(e.history) {
+		return
+	}
+	e.histPos++
+	if e.histPos == len(e.history) {
+		e.set(e.draft)
+		return
+	}
+	e.set(e.history[e.histPos])
+}
+
+// submit clears the buffer and returns its text. Answers are added to the
+// history unless remember is false (e.g. masked input).
+func (e *lineEditor) submit(remember bool) string {
+	s := e.String()
+	if remember && strings.TrimSpace(s) != "" && (len(e.history) == 0 || e.history[len(e.history)-1] != s) {
+		e.history = append(e.history, s)
+	}
+	e.clear()
+	return s
+}
+
+// clear empties the buffer and leaves history browsing
+func (e *lineEditor) clear() {
+	e.text = nil
+	e.pos = 0
+	e.histPos = len(e.history)
+	e.draft = ""
+}
+
+// handleKey applies an editing key; other keys are ignored
+func (e *lineEditor) handleKey(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyRunes:
+		e.insert(string(msg.Runes))
+	case tea.KeySpace:
+		e.insert(" ")
+	case tea.KeyBackspace:
+		if e.pos > 0 {
+			e.deleteRange(e.pos-1, e.pos)
+		}
+	case tea.KeyDelete:
+		if e.pos < len(e.text) {
+			e.deleteRange(e.pos, e.pos+1)
+		}
+	case tea.KeyLeft:
+		if e.pos > 0 {
+			e.pos--
+		}
+	case tea.KeyRight:
+		if e.pos < len(e.text) {
+			e.pos++
+		}
+	case tea.KeyHome, tea.KeyCtrlA:
+		e.pos = 0
+	case tea.KeyEnd, tea.KeyCtrlE:
+		e.pos = len(e.text)
+	case tea.KeyCtrlW:
+		e.deleteRange(e.wordStart(), e.pos)
+	case tea.KeyCtrlU:
+		e.deleteRange(0, e.pos)
+	case tea.KeyCtrlK:
+		e.deleteRange(e.pos, len(e.text))
+	case tea.KeyUp:
+		e.historyPrev()
+	case tea.KeyDown:
+		e.historyNext()
+	}
+}
+
+// render draws the text in style with a cursor: a block in blockStyle at the
+// end of the line, or the character under the cursor reversed. mask, if
+// non-zero, replaces every character.
+func (e *lineEditor) render(mask rune, style, blockStyle lipgloss.Style) string {
+	runes := e.text
+	if mask != 0 {
+		runes = []rune(strings.Repeat(string(mask), len(e.text)))
+	}
+	if e.pos >= len(runes) {
+		return style.Render(string(runes)) + blockStyle.Render("█")
+	}
+	return style.Render(string(runes[:e.pos])) + style.Copy().Reverse(true).Render(string(runes[e.pos])) + style.Render(string(runes[e.pos+1:]))
+}
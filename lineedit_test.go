@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func editKeys(e *lineEditor, keys ...string) {
+	special := map[string]tea.KeyType{
+		"left": tea.KeyLeft, "right": tea.KeyRight, "home": tea.KeyHome, "end": tea.KeyEnd,
+		"backspace": tea.KeyBackspace, "delete": tea.KeyDelete, "up": tea.KeyUp, "down": tea.KeyDown,
+		"ctrl+w": tea.KeyCtrlW, "ctrl+u": tea.KeyCtrlU, "ctrl+k": tea.KeyCtrlK, " ": tea.KeySpace,
+	}
+	for _, k := range keys {
+		if kt, ok := special[k]; ok {
+			e.handleKey(tea.KeyMsg{Type: kt})
+			continue
+		}
+		e.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+}
+
+func TestLineEditorCursorMovement(t *testing.T) {
+	var e lineEditor
+	editKeys(&e, "wrld", "home", "right", "o", "end", "!", "left", "left", "delete")
+	if got := e.String(); got != "worl!" || e.pos != 4 {
+		t.Errorf("expected forward delete under the cursor, got %q at %d", got, e.pos)
+	}
+}
+
+func TestLineEditorRuneAwareBackspace(t *testing.T) {
+	var e lineEditor
+	editKeys(&e, "añ€😀", "backspace", "backspace")
+	if got := e.String(); got != "añ" {
+		t.Errorf("expected whole characters deleted, got %q", got)
+	}
+	editKeys(&e, "left", "backspace")
+	if got := e.String(); got != "ñ" || e.pos != 0 {
+		t.Errorf("expected deletion before the cursor, got %q at %d", got, e.pos)
+	}
+}
+
+func TestLineEditorKillKeys(t *testing.T) {
+	var e lineEditor
+	editKeys(&e, "us-east-1 prod", " ", " ", "ctrl+w")
+	if got := e.String(); got != "us-east-1 " {
+		t.Errorf("expected Ctrl+W to delete the previous word, got %q", got)
+	}
+	editKeys(&e, "left", "left", "ctrl+u")
+	if got := e.String(); got != "1 " {
+		t.Errorf("expected Ctrl+U to delete to the start, got %q", got)
+	}
+	editKeys(&e, "ctrl+k")
+	if got := e.String(); got != "" {
+		t.Errorf("expected Ctrl+K to delete to the end, got %q", got)
+	}
+}
+
+func TestLineEditorPaste(t *testing.T) {
+	var e lineEditor
+	editKeys(&e, "ab", "left", "pasted\r\ntext")
+	if got := e.String(); got != "apastedtextb" {
+		t.Errorf("expected the paste inserted at the cursor without newlines, got %q", got)
+	}
+}
+
+func TestLineEditorHistory(t *testing.T) {
+	var e lineEditor
+	editKeys(&e, "first")
+	e.submit(true)
+	editKeys(&e, "secret")
+	e.submit(false)
+	editKeys(&e, "second")
+	e.submit(true)
+
+	editKeys(&e, "draft", "up")
+	if got := e.String(); got != "second" {
+		t.Errorf("expected the newest answer, got %q", got)
+	}
+	editKeys(&e, "up", "up")
+	if got := e.String(); got != "first" {
+		t.Errorf("expected to stop at the oldest answer without the masked one, got %q", got)
+	}
+	editKeys(&e, "down", "down")
+	if got := e.String(); got != "draft" {
+		t.Errorf("expected the draft restored, got %q", got)
+	}
+}
+
+func TestInputModeEditing(t *testing.T) {
+	m, f := guardModel(t)
+	m = typeKeys(m, "i", "ys", "left", "e", "enter")
+	if got := ptyWritten(t, f); got != "yes\n" {
+		t.Fatalf("expected the edited answer written, got %q", got)
+	}
+
+	m.prompt = "Enter a value:"
+	m = typeKeys(m, "i", "up")
+	if got := m.input.String(); got != "yes" {
+		t.Errorf("expected up to recall the previous answer, got %q", got)
+	}
+	if rendered := stripANSI(m.renderPrompt()); !strings.Contains(rendered, "yes█") {
+		t.Errorf("expected the cursor at the end of the recalled answer, got %q", rendered)
+	}
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// lockRetryTimeout is passed as -lock-timeout when re-running a command that
+// failed to acquire the state lock.
+const lockRetryTimeout = "5m"
+
+// lockCreatedLayout is the format Terraform uses for the lock's Created field
+const lockCreatedLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+var (
+	lockFieldPattern = regexp.MustCompile(`^\s*(ID|Path|Operation|Who|Version|Created|Info):\s*(.*?)\s*$`)
+	// lockTimeoutPattern matches an existing -lock-timeout argument
+	lockTimeoutPattern = regexp.MustCompile(`^-?-lock-timeout(=.*)?$`)
+)
+
+// lockingSubcommands are the Terraform subcommands that take -lock-timeout
+var lockingSubcommands = map[string]bool{
+	"plan": true, "apply": true, "destroy": true, "refresh": true,
+	"import": true, "taint": true, "untaint": true, "init": true,
+}
+
+// LockInfo is the Lock Info table printed with "Error acquiring the state lock"
+type LockInfo struct {
+	ID        string
+	Path      string
+	Operation string
+	Who       string
+	Version   string
+	Created   time.Time // Zero if the Created field could not be parsed
+	Info      string
+}
+
+// parseLockInfo extracts the lock record from a state lock diagnostic
+func parseLockInfo(diag Diagnostic) (LockInfo, bool) {
+	if !strings.Contains(diag.Summary, "Error acquiring the state lock") {
+		return LockInfo{}, false
+	}
+
+	var info LockInfo
+	inTable := false
+	for _, detail := range diag.Detail {
+		clean := stripANSI(detail.Content)
+		if strings.TrimSpace(clean) == "Lock Info:" {
+			inTable = true
+			continue
+		}
+		if !inTable {
+			continue
+		}
+		match := lockFieldPattern.FindStringSubmatch(clean)
+		if match == nil {
+			if info.ID != "" {
+				break
+			}
+			continue
+		}
+		switch match[1] {
+		case "ID":
+			info.ID = match[2]
+		case "Path":
+			info.Path = match[2]
+		case "Operation":
+			info.Operation = match[2]
+		case "Who":
+			info.Who = match[2]
+		case "Version":
+			info.Version = match[2]
+		case "Created":
+			if created, err := time.Parse(lockCreatedLayout, match[2]); err == nil {
+				info.Created = created
+			}
+		case "Info":
+			info.Info = match[2]
+		}
+	}
+	return info, info.ID != ""
+}
+
+// formatLockAge renders a lock age as a short human-readable duration
+func formatLockAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
+
+// lockCardLines returns the text of the lock card shown under the diagnostic
+func lockCardLines(info LockInfo, now time.Time, canRerun bool) []string {
+	age := "unknown age"
+	if !info.Created.IsZero() {
+		age = formatLockAge(now.Sub(info.Created)) + " old"
+	}
+	lines := []string{
+		fmt.Sprintf("┌ State lock held by %s (%s)", info.Who, age),
+		"│ ID:        " + info.ID,
+		"│ Path:      " + info.Path,
+		"│ Operation: " + strings.TrimPrefix(info.Operation, "OperationType"),
+		"│ Version:   " + info.Version,
+	}
+	if canRerun {
+		lines = append(lines, fmt.Sprintf("└ R: re-run with -lock-timeout=%s   U: force-unlock", lockRetryTimeout))
+	} else {
+		lines = append(lines, "└ U: force-unlock")
+	}
+	return lines
+}
+
+// withLockTimeout returns args with -lock-timeout set on the Terraform subcommand.
+// An existing -lock-timeout is replaced, along with its value if that was a
+// separate argument. It returns nil if no subcommand that supports locking is
+// found.
+func withLockTimeout(args []string, timeout string) []string {
+	flag := "-lock-timeout=" + timeout
+	for i, a := range args {
+		if match := lockTimeoutPattern.FindStringSubmatch(a); match != nil {
+			rest := args[i+1:]
+			if match[1] == "" && len(rest) > 0 {
+				rest = rest[1:]
+			}
+			out := append([]string{}, args[:i]...)
+			out = append(out, flag)
+			return append(out, rest...)
+		}
+	}
+	for i, a := range args {
+		if lockingSubcommands[a] {
+			out := append([]string{}, args[:i+1]...)
+			out = append(out, flag)
+			return append(out, args[i+1:]...)
+		}
+	}
+	return nil
+}
+
+// forceUnlockCommand builds "<terraform> [-chdir=...] force-unlock -force <id>"
+// from the wrapped command, defaulting to terraform in pipe mode.
+func forceUnlockCommand(command []string, id string) *exec.Cmd {
+	bin := "terraform"
+	var args []string
+	if len(command) > 0 {
+		bin = command[0]
+		for _, a := range command[1:] {
+			if strings.HasPrefix(a, "-chdir=") {
+				args = append(args, a)
+			}
+		}
+	}
+	args = append(args, "force-unlock", "-force", id)
+	return exec.Command(bin, args...)
+}
+
+// forceUnlockFinishedMsg is sent when the force-unlock command exits
+type forceUnlockFinishedMsg struct {
+	id  string
+	err error
+}
+
+// lockAt returns the diagnostic index and lock record for the line at lineIdx
+func (m Model) lockAt(lineIdx int) (int, LockInfo, bool) {
+	if lineIdx < 0 || lineIdx >= len(m.lines) {
+		return -1, LockInfo{}, false
+	}
+	line := m.lines[lineIdx]
+	switch line.Type {
+	case LineTypeDiagnostic, LineTypeDiagnosticDetail, LineTypeLockCard:
+	default:
+		return -1, LockInfo{}, false
+	}
+	if line.DiagIdx < 0 || line.DiagIdx >= len(m.diagnostics) {
+		return -1, LockInfo{}, false
+	}
+	info, ok := parseLockInfo(m.diagnostics[line.DiagIdx])
+	return line.DiagIdx, info, ok
+}
+
+// rerunWithLockTimeout quits the current session so main re-runs the wrapped
+// command with -lock-timeout added.
+func (m *Model) rerunWithLockTimeout() tea.Cmd {
+	if _, _, ok := m.lockAt(m.cursor); !ok || len(m.command) == 0 {
+		return nil
+	}
+	args := withLockTimeout(m.command, lockRetryTimeout)
+	if args == nil {
+		return nil
+	}
+	m.rerunArgs = args
+	if m.cancelFunc != nil {
+		m.cancelFunc()
+	}
+	return tea.Quit
+}
+
+// confirmForceUnlock opens a dialog that requires typing the lock ID before
+// running force-unlock.
+func (m *Model) confirmForceUnlock() {
+	_, info, ok := m.lockAt(m.cursor)
+	if !ok {
+		return
+	}
+	cmd := forceUnlockCommand(m.command, info.ID)
+	m.dialog = &confirmDialog{
+		title: "FORCE-UNLOCK",
+		lines: []string{
+			fmt.Sprintf("This removes the lock held by %s for %s.", info.Who, strings.TrimPrefix(info.Operation, "OperationType")),
+			"Only do this if that operation is no longer running.",
+			"Will run: " + strings.Join(cmd.Args, " "),
+			"Type the lock ID to confirm:",
+		},
+		expect: []string{info.ID},
+		onConfirm: func(m *Model) tea.Cmd {
+			return tea.ExecProcess(cmd, func(err error) tea.Msg {
+				return forceUnlockFinishedMsg{id: info.ID, err: err}
+			})
+		},
+	}
+}
+
+// appendLockCardLines emits the lock card for a state lock diagnostic
+func (m *Model) appendLockCardLines(diagIdx int) {
+	info, ok := parseLockInfo(m.diagnostics[diagIdx])
+	if !ok {
+		return
+	}
+	for j, text := range lockCardLines(info, time.Now(), len(m.command) > 0) {
+		for _, w := range wrapText(text, m.width-4, 2) {
+			m.lines = append(m.lines, Line{
+				Type:        LineTypeLockCard,
+				DiagIdx:     diagIdx,
+				ResourceIdx: -1,
+				AttrIdx:     j,
+				Content:     w,
+			})
+		}
+	}
+}
+
+// renderLockCardLine renders a line of the state lock card
+func (m Model) renderLockCardLine(line Line, isSelected bool) string {
+	t := m.theme()
+	if isSelected {
+		return t.Selected.Render("►   " + line.Content)
+	}
+	if line.AttrIdx == 0 {
+		return "    " + t.Warning.Render(line.Content)
+	}
+	return "    " + t.Default.Render(line.Content)
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const stateLockOutput = `╷
+│ Error: Error acquiring the state lock
+│ 
+│ Error message: ConditionalCheckFailedException: The conditional request
+│ failed
+│ Lock Info:
+│   ID:        9db590f1-b6fe-c5f2-2678-8804f089deba
+│   Path:      my-bucket/prod/terraform.tfstate
+│   Operation: OperationTypeApply
+│   Who:       alice@build-agent-7
+│   Version:   1.5.7
+│   Created:   2026-10-15 08:30:00.123456789 +0000 UTC
+│   Info:      
+│ 
+│ 
+│ Terraform acquires a state lock to protect the state from being written
+│ by multiple users at the same time. Please resolve the issue above and try
+│ again. For most commands, you can disable locking with the "-lock=false"
+│ flag, but this is not recommended.
+╵
+`
+
+func parseStateLockDiag(t *testing.T) Diagnostic {
+	t.Helper()
+	m := &Model{streamChan: make(chan StreamMsg, 10)}
+	diags, _, _, _ := collectStreamMsgs(m, stateLockOutput)
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+	}
+	return *diags[0]
+}
+
+func TestParseLockInfo(t *testing.T) {
+	info, ok := parseLockInfo(parseStateLockDiag(t))
+	if !ok {
+		t.Fatal("expected lock info to be parsed")
+	}
+
+	want := LockInfo{
+		ID:        "9db590f1-b6fe-c5f2-2678-8804f089deba",
+		Path:      "my-bucket/prod/terraform.tfstate",
+		Operation: "OperationTypeApply",
+		Who:       "alice@build-agent-7",
+		Version:   "1.5.7",
+		Created:   time.Date(2026, 10, 15, 8, 30, 0, 123456789, time.UTC),
+	}
+	if !info.Created.Equal(want.Created) {
+		t.Errorf("expected created %v, got %v", want.Created, info.Created)
+	}
+	info.Created = want.Created
+	if info != want {
+		t.Errorf("expected %+v, got %+v", want, info)
+	}
+
+	if _, ok := parseLockInfo(Diagnostic{Severity: "error", Summary: "Invalid value"}); ok {
+		t.Error("expected no lock info for an unrelated diagnostic")
+	}
+}
+
+func TestLockCardShowsAge(t *testing.T) {
+	info, _ := parseLockInfo(parseStateLockDiag(t))
+	now := info.Created.Add(3*24*time.Hour + 4*time.Hour)
+
+	lines := lockCardLines(info, now, true)
+	if !strings.Contains(lines[0], "alice@build-agent-7 (3d 4h old)") {
+		t.Errorf("unexpected card heading %q", lines[0])
+	}
+	if !strings.Contains(lines[len(lines)-1], "-lock-timeout="+lockRetryTimeout) {
+		t.Errorf("expected re-run action, got %q", lines[len(lines)-1])
+	}
+	if last := lockCardLines(info, now, false); strings.Contains(last[len(last)-1], "re-run") {
+		t.Error("re-run action should not be offered in pipe mode")
+	}
+}
+
+func TestFormatLockAge(t *testing.T) {
+	testCases := map[time.Duration]string{
+		30 * time.Second:            "just now",
+		42 * time.Minute:            "42m",
+		5*time.Hour + 7*time.Minute: "5h 7m",
+		49 * time.Hour:              "2d 1h",
+	}
+	for d, want := range testCases {
+		if got := formatLockAge(d); got != want {
+			t.Errorf("formatLockAge(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestWithLockTimeout(t *testing.T) {
+	testCases := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"terraform", "apply", "-auto-approve"}, []string{"terraform", "apply", "-lock-timeout=5m", "-auto-approve"}},
+		{[]string{"terraform", "-chdir=infra", "plan"}, []string{"terraform", "-chdir=infra", "plan", "-lock-timeout=5m"}},
+		{[]string{"terraform", "plan", "-lock-timeout=30s"}, []string{"terraform", "plan", "-lock-timeout=5m"}},
+		{[]string{"terraform", "plan", "-lock-timeout", "30s", "-out=tfplan"}, []string{"terraform", "plan", "-lock-timeout=5m", "-out=tfplan"}},
+		{[]string{"terraform", "fmt"}, nil},
+	}
+	for _, tc := range testCases {
+		if got := withLockTimeout(tc.args, "5m"); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("withLockTimeout(%v) = %v, want %v", tc.args, got, tc.want)
+		}
+	}
+}
+
+func TestForceUnlockCommand(t *testing.T) {
+	cmd := forceUnlockCommand([]string{"tofu", "-chdir=infra", "apply"}, "abc")
+	if want := []string{"tofu", "-chdir=infra", "force-unlock", "-force", "abc"}; !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("expected %v, got %v", want, cmd.Args)
+	}
+	if cmd := forceUnlockCommand(nil, "abc"); cmd.Args[0] != "terraform" {
+		t.Errorf("expected terraform in pipe mode, got %v", cmd.Args)
+	}
+}
+
+func TestForceUnlockRequiresTypedID(t *testing.T) {
+	m := Model{tab: TabLog, width: 120, diagnostics: []Diagnostic{parseStateLockDiag(t)}}
+	m.rebuildLines()
+
+	updated, _ := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("U")})
+	m = updated.(Model)
+	if m.dialog == nil {
+		t.Fatal("expected force-unlock dialog to open")
+	}
+
+	updated, _ = m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("yes")})
+	m = updated.(Model)
+	updated, cmd := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd != nil || m.dialog == nil || !m.dialog.mismatch {
+		t.Fatal("expected a wrong answer to keep the dialog open")
+	}
+
+	updated, _ = m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("9db590f1-b6fe-c5f2-2678-8804f089deba")})
+	m = updated.(Model)
+	updated, cmd = m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if cmd == nil || m.dialog != nil {
+		t.Error("expected the lock ID to confirm and run force-unlock")
+	}
+}
+
+func TestRerunWithLockTimeout(t *testing.T) {
+	m := Model{
+		tab:         TabLog,
+		width:       120,
+		command:     []string{"terraform", "plan"},
+		diagnostics: []Diagnostic{parseStateLockDiag(t)},
+	}
+	m.rebuildLines()
+
+	updated, cmd := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("expected the session to quit for a re-run")
+	}
+	if want := []string{"terraform", "plan", "-lock-timeout=5m"}; !reflect.DeepEqual(m.rerunArgs, want) {
+		t.Errorf("expected rerun args %v, got %v", want, m.rerunArgs)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+// appendDiagnosticsBefore emits, one by one, the diagnostics from index next
+// onwards that arrived before log line pos. It returns the index of the first
+// diagnostic not emitted.
+func (m *Model) appendDiagnosticsBefore(next, pos int) int {
+	for ; next < len(m.diagnostics) && m.diagnostics[next].LogPos <= pos; next++ {
+		d := m.diagnostics[next]
+		m.appendDiagnosticGroupLines(DiagnosticGroup{
+			Severity: d.Severity,
+			Summary:  d.Summary,
+			Indices:  []int{next},
+			Similar:  similarCount(d),
+		})
+	}
+	return next
+}
+
+// toggleLogLayout switches the Log tab between diagnostics at the end and
+// diagnostics interleaved where they occurred, keeping the cursor on the
+// same diagnostic or log line where possible.
+func (m *Model) toggleLogLayout() {
+	if m.tab != TabLog {
+		return
+	}
+	var anchor Line
+	if m.cursor >= 0 && m.cursor < len(m.lines) {
+		anchor = m.lines[m.cursor]
+	}
+
+	m.chronological = !m.chronological
+	m.autoScroll = false
+	m.rebuildLines()
+
+	for i, line := range m.lines {
+		if line.Type == anchor.Type && line.DiagIdx == anchor.DiagIdx && line.AttrIdx == anchor.AttrIdx && line.Content == anchor.Content {
+			m.cursor = i
+			break
+		}
+	}
+	m.clampCursor()
+	m.ensureCursorVisible()
+}
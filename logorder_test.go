@@ -0,0 +1,116 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const interleavedApply = `aws_vpc.main: Creating...
+aws_vpc.main: Creation complete after 2s
+aws_instance.web: Creating...
+╷
+│ Error: creating EC2 Instance: InvalidAMIID.NotFound
+│ 
+│   with aws_instance.web,
+│   on main.tf line 12, in resource "aws_instance" "web":
+╵
+aws_s3_bucket.logs: Creating...
+aws_s3_bucket.logs: Creation complete after 1s
+`
+
+func logLineTexts(m Model) []string {
+	var out []string
+	for _, l := range m.lines {
+		out = append(out, stripANSI(l.Content))
+	}
+	return out
+}
+
+func indexOf(lines []string, substr string) int {
+	for i, l := range lines {
+		if strings.Contains(l, substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestStreamRecordsDiagnosticPosition(t *testing.T) {
+	m := streamIntoModel(Model{tab: TabLog, width: 200}, interleavedApply)
+	if len(m.diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(m.diagnostics))
+	}
+	if idx := indexOf(m.logs, "aws_s3_bucket.logs: Creating..."); m.diagnostics[0].LogPos != idx {
+		t.Errorf("LogPos = %d, want %d (before the first log after the error)", m.diagnostics[0].LogPos, idx)
+	}
+}
+
+func TestChronologicalLayout(t *testing.T) {
+	m := streamIntoModel(Model{tab: TabLog, width: 200}, interleavedApply)
+	m = typeKeys(m, "3") // The error switched to the Diagnostics tab
+
+	lines := logLineTexts(m)
+	if diag, last := indexOf(lines, "InvalidAMIID"), indexOf(lines, "aws_s3_bucket.logs: Creation complete"); diag < last {
+		t.Fatalf("expected diagnostics at the end by default, got diag at %d, last log at %d", diag, last)
+	}
+
+	m = typeKeys(m, "t")
+	if !m.chronological {
+		t.Fatal("expected t to switch to the chronological layout")
+	}
+	lines = logLineTexts(m)
+	creating, diag, after := indexOf(lines, "aws_instance.web: Creating..."), indexOf(lines, "InvalidAMIID"), indexOf(lines, "aws_s3_bucket.logs: Creating...")
+	if !(creating < diag && diag < after) {
+		t.Errorf("expected the error between the logs around it, got %d < %d < %d", creating, diag, after)
+	}
+	if !strings.Contains(stripANSI(m.renderFooter()), "chronological") {
+		t.Error("expected the footer to show the chronological layout")
+	}
+
+	m = typeKeys(m, "t")
+	if m.chronological {
+		t.Error("expected t to restore the errors-at-end layout")
+	}
+}
+
+func TestChronologicalToggleKeepsCursor(t *testing.T) {
+	m := streamIntoModel(Model{tab: TabLog, width: 200, height: 40}, interleavedApply)
+	m = typeKeys(m, "3")
+	m.cursor = indexOf(logLineTexts(m), "InvalidAMIID")
+	m = typeKeys(m, "t")
+	if line := m.lines[m.cursor]; line.Type != LineTypeDiagnostic {
+		t.Errorf("expected the cursor to follow the diagnostic, got %+v", line)
+	}
+}
+
+func TestChronologicalIgnoredOutsideLogTab(t *testing.T) {
+	m := streamIntoModel(Model{tab: TabPlan, width: 200}, interleavedApply)
+	m = typeKeys(m, "1", "t")
+	if m.chronological {
+		t.Error("expected t to do nothing outside the Log tab")
+	}
+}
+
+func TestChronologicalLayoutHidesGroupBadge(t *testing.T) {
+	diag := Diagnostic{Severity: "warning", Summary: "Deprecated attribute", Detail: []DiagnosticLine{{Content: "  on main.tf line 3:", IsMarker: true}}}
+	m := Model{tab: TabLog, width: 120, height: 30, ready: true, logs: []string{"a", "b"}}
+	m.diagnostics = []Diagnostic{diag, diag}
+	m.diagnostics[1].LogPos = 1
+	m.rebuildLines()
+
+	badge := func() bool {
+		for i, l := range m.lines {
+			if l.Type == LineTypeDiagnostic && strings.Contains(stripANSI(m.renderLine(i)), "×2") {
+				return true
+			}
+		}
+		return false
+	}
+	if !badge() {
+		t.Fatal("expected the grouped layout to show the occurrence count")
+	}
+	m = typeKeys(m, "t")
+	if badge() {
+		t.Error("expected no occurrence count on the separate chronological entries")
+	}
+}
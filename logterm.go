@@ -0,0 +1,118 @@
+package main
+
+import "strings"
+
+// logWindowRows is how many recent rows cursor movement in the output can
+// reach back to
+const logWindowRows = 200
+
+// logTerminal turns raw output into log lines the way a terminal would show
+// them: carriage returns and cursor movement redraw rows instead of adding
+// new lines. A row becomes a line once the cursor has moved below it; later
+// redraws of a line are reported as updates.
+type logTerminal struct {
+	screen    *vtScreen
+	base      int         // Line number of the screen's top row
+	committed int         // Number of lines passed to onLine so far
+	ids       map[int]int // ID returned by onLine, by line number, while on screen
+	texts     map[int]string
+
+	onLine   func(line string) int     // Receives each new line; returns an ID for updates, or -1
+	onUpdate func(id int, line string) // Receives redraws of a line onLine gave an ID
+}
+
+// newLogTerminal returns a log terminal calling onLine and onUpdate
+func newLogTerminal(onLine func(string) int, onUpdate func(int, string)) *logTerminal {
+	t := &logTerminal{
+		screen:   newVTScreen(0, logWindowRows),
+		ids:      make(map[int]int),
+		texts:    make(map[int]string),
+		onLine:   onLine,
+		onUpdate: onUpdate,
+	}
+	t.screen.newlineMode = true
+	t.screen.onScroll = t.scrolled
+	return t
+}
+
+// Write feeds output to the terminal and emits the lines it completed
+func (t *logTerminal) Write(p []byte) (int, error) {
+	t.screen.Write(p)
+	t.sync(t.screen.y)
+	return len(p), nil
+}
+
+// partial returns the row the cursor is on, if it is not a line yet. This is
+// where prompts waiting for input appear.
+func (t *logTerminal) partial() string {
+	if t.base+t.screen.y < t.committed {
+		return ""
+	}
+	return t.screen.renderRow(t.screen.y, false)
+}
+
+// flush emits everything left on screen at the end of the output. Rows after
+// the cursor's are dropped if blank, like an unterminated last line.
+func (t *logTerminal) flush() {
+	end := t.screen.y
+	if strings.TrimSpace(stripANSI(t.screen.renderRow(end, false))) != "" {
+		end++
+	}
+	t.sync(end)
+}
+
+// sync commits rows [committed, end) and reports redraws of committed rows
+func (t *logTerminal) sync(end int) {
+	for y := 0; y < t.screen.height; y++ {
+		n := t.base + y
+		switch {
+		case n >= t.committed && y < end:
+			t.commit(y)
+		case n < t.committed && t.screen.dirty[y]:
+			t.update(y)
+		}
+	}
+	t.screen.clearDirty()
+}
+
+// scrolled handles the top n rows leaving the screen: they are final
+func (t *logTerminal) scrolled(n int) {
+	for y := 0; y < n; y++ {
+		line := t.base + y
+		if line >= t.committed {
+			t.commit(y)
+		} else if t.screen.dirty[y] {
+			t.update(y)
+		}
+		delete(t.ids, line)
+		delete(t.texts, line)
+	}
+	t.base += n
+}
+
+// commit passes row y to onLine
+func (t *logTerminal) commit(y int) {
+	line := t.base + y
+	text := t.screen.renderRow(y, false)
+	t.committed = line + 1
+	if id := t.onLine(text); id >= 0 {
+		t.ids[line] = id
+		t.texts[line] = text
+	}
+}
+
+// update reports a redraw of row y if it changed a line with an ID. Rows
+// that were cleared keep their last text.
+func (t *logTerminal) update(y int) {
+	line := t.base + y
+	id, ok := t.ids[line]
+	if !ok {
+		return
+	}
+	text := t.screen.renderRow(y, false)
+	if text == t.texts[line] || strings.TrimSpace(stripANSI(text)) == "" {
+		return
+	}
+	t.texts[line] = text
+	t.onUpdate(id, text)
+}
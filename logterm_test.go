@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/creack/pty"
+)
+
+func TestLogCarriageReturnProgress(t *testing.T) {
+	m := Model{streamChan: make(chan StreamMsg, streamBufferSize)}
+	input := "- Installing hashicorp/aws v5.0.0...\r- Installing hashicorp/aws v5.0.0... 40%\r- Installed hashicorp/aws v5.0.0\x1b[K\r\nDone\r\n"
+	_, logs, _, _ := collectStreamMsgs(&m, input)
+	want := []string{"- Installed hashicorp/aws v5.0.0", "Done"}
+	if strings.Join(logs, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", logs, want)
+	}
+}
+
+func TestLogCursorMovementRedraw(t *testing.T) {
+	m := Model{width: 80, height: 24, ready: true}
+	input := "module.a: Creating...\nmodule.b: Creating...\n" +
+		"\x1b[2A\x1b[2Kmodule.a: Creation complete\n\x1b[1B" +
+		"Apply complete!\n"
+	m = streamIntoModel(m, input)
+	want := []string{"module.a: Creation complete", "module.b: Creating...", "Apply complete!"}
+	if strings.Join(m.logs, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", m.logs, want)
+	}
+}
+
+func TestLogOversizedCursorMove(t *testing.T) {
+	m := Model{streamChan: make(chan StreamMsg, streamBufferSize)}
+	input := "\x1b[5000000Cx\r\n\x1b[5000000@y\r\n"
+	_, logs, _, _ := collectStreamMsgs(&m, input)
+	for _, line := range logs {
+		if len(line) > vtMaxLineWidth {
+			t.Errorf("expected log lines capped at %d columns, got %d", vtMaxLineWidth, len(line))
+		}
+	}
+}
+
+func TestLogRedrawKeepsParsing(t *testing.T) {
+	m := Model{width: 80, height: 24, ready: true}
+	input := "Refreshing...\r\x1b[K  # null_resource.a will be created\n" +
+		"  + resource \"null_resource\" \"a\" {\n" +
+		"      + id = (known after apply)\n" +
+		"    }\n" +
+		"Plan: 1 to add\n" +
+		"\x1b[1mDo you want to perform these actions?\x1b[0m\n  Enter a value: "
+	m = streamIntoModel(m, input)
+	if len(m.resources) != 1 || m.resources[0].Address != "null_resource.a" {
+		t.Fatalf("expected the redrawn header to be parsed, got %+v", m.resources)
+	}
+	if m.prompt != "Enter a value:" {
+		t.Errorf("expected the prompt detected on the partial row, got %q", m.prompt)
+	}
+}
+
+func TestWindowSizeForwardedToPTY(t *testing.T) {
+	master, tty, err := pty.Open()
+	if err != nil {
+		t.Skipf("no PTY available: %v", err)
+	}
+	defer master.Close()
+	defer tty.Close()
+
+	m := Model{ptyFile: master, screen: newVTScreen(80, 24)}
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 132, Height: 40})
+	m = updated.(Model)
+
+	rows, cols, err := pty.Getsize(tty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows != 39 || cols != 132 {
+		t.Errorf("expected the child to see 132x39, got %dx%d", cols, rows)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -39,6 +40,12 @@ const (
 	LineTypeDiagnostic
 	LineTypeDiagnosticDetail
 	LineTypeLog
+	LineTypeSource
+	LineTypeOccurrence
+	LineTypeHint
+	LineTypeCrash
+	LineTypeLockCard
+	LineTypeDiff
 )
 
 // RenderingMode represents the active color palette
@@ -104,6 +111,13 @@ type Diagnostic struct {
 	Summary  string           // Main message
 	Detail   []DiagnosticLine // Additional detail lines
 	Expanded bool             // Whether details are expanded in UI
+	LogPos   int              // Number of log lines received before this diagnostic
+
+	ShowSource bool // Whether the source snippet is shown in UI
+
+	Category string // Classifier category (auth, quota, ...), empty if unclassified
+	Hint     string // Remediation hint for the category
+	ShowHint bool   // Whether the remediation hint is shown in UI
 }
 
 // Line represents a single display line in the UI
@@ -113,6 +127,7 @@ type Line struct {
 	DiagIdx     int      // Index into diagnostics slice (-1 if not applicable)
 	AttrIdx     int      // Index into attributes/details (-1 for headers)
 	Content     string   // Raw content for display
+	Aside       string   // Right-hand column of side-by-side diff lines
 }
 
 // StreamMsg carries parsed content from the input stream to the UI
@@ -121,8 +136,16 @@ type StreamMsg struct {
 	Diagnostic      *Diagnostic
 	LogLine         *string
 	Prompt          *string // Partial line that looks like a prompt (no trailing newline)
-	Done            bool    // Signals end of input stream
-	ReceivedContent bool    // True if any non-empty content was received (only meaningful with Done)
+	Output          []byte  // Raw bytes read from the PTY, for the terminal emulator
+	LogUpdate       *LogUpdate
+	Done            bool // Signals end of input stream
+	ReceivedContent bool // True if any non-empty content was received (only meaningful with Done)
+}
+
+// LogUpdate replaces a log line that the output has since redrawn
+type LogUpdate struct {
+	Index int // Position of the line among the LogLines sent by the stream
+	Line  string
 }
 
 // tickMsg triggers periodic UI updates for batched rendering
@@ -140,36 +163,100 @@ type Model struct {
 	resources   []ResourceChange
 	diagnostics []Diagnostic
 	logs        []string
+	streamLogs  []int  // Index in logs of each LogLine received from the stream
 	lines       []Line // Computed display lines based on expand state
 
+	snippets   map[int]*SourceSnippet // Source snippets loaded on demand, keyed by diagnostic index
+	diagGroups []DiagnosticGroup      // Diagnostics grouped by severity and summary
+
+	crashes         []CrashInfo  // Panic stack traces detected in the logs
+	expandedCrashes map[int]bool // Unfolded crash blocks, keyed by first log index
+	crashScan       int          // Logs before this index have been scanned for crashes
+	crashDetail     int          // Logs before this index have been scanned for crash log paths
+	crashDiags      int          // Diagnostics scanned for crashed plugins
+	crashOpen       bool         // The last crash was still being printed at the last scan
+
 	// UI state
-	cursor        int  // Current line index
-	width         int  // Terminal width
-	height        int  // Terminal height
-	offset        int  // Scroll offset
-	ready         bool // Whether initial size is known
-	showLogs      bool // Toggle between log view and plan view
-	autoScroll    bool // Auto-scroll to bottom on new content
+	cursor        int                // Current line index
+	width         int                // Terminal width
+	height        int                // Terminal height
+	offset        int                // Scroll offset
+	ready         bool               // Whether initial size is known
+	tab           Tab                // Active tab
+	tabStates     [tabCount]tabState // Saved state of the inactive tabs
+	fixedView     bool               // Tab was chosen with --view; new content doesn't switch it
+	autoScroll    bool               // Auto-scroll to bottom on new content
 	renderingMode RenderingMode
 	done          bool // Input stream finished
 	needsSync     bool // Pending rebuild of lines slice
 
 	// PTY/Interactive mode
+	command   []string // Wrapped command and its arguments (empty in pipe mode)
+	rerunArgs []string // Command to run after this session ends, if any
 	ptyFile   *os.File
-	inputMode bool   // Currently accepting user input
-	userInput string // Buffer for user typing
-	prompt    string // Active prompt from stream
+	inputMode bool       // Currently accepting user input
+	input     lineEditor // Buffer for user typing
+	prompt    string     // Active prompt from stream
+
+	promptCtx  promptContext // Variable or question printed above the prompt
+	secrets    []string      // Masked answers redacted from the logs
+	redactEcho bool          // Terraform's echo of a masked answer is pending
+	screenEcho string        // Masked answer whose echo hasn't reached the screen yet
+
+	dialog *confirmDialog // Open confirmation dialog, if any
+
+	screen      *vtScreen // Emulated terminal fed with the raw PTY output
+	passthrough bool      // Keys go straight to the PTY and the screen is shown
+
+	// Search
+	searching      bool         // Typing a search query
+	searchInput    string       // Query being typed
+	searchBackward bool         // Query was started with ?
+	searchOrigin   int          // Cursor position when the search started
+	searchExpanded []int        // Resources expanded by the query being typed
+	search         *searchState // Last submitted search
+
+	// Plan view filtering
+	pendingKey   string          // First key of a two-key chord (e.g. "f")
+	actionFilter map[string]bool // Actions shown in the Plan view (empty = all)
+
+	addrFilter    addressFilter // Address include/exclude patterns
+	editingFilter bool          // Typing in the address filter prompt
+	filterInput   string        // Address filter being typed
+	filterErr     string        // Error from the last submitted address filter
+
+	palette *palette // Open "go to resource" palette, nil when closed
+
+	splitPane    bool // Plan view drawn as resource list + detail pane
+	focusDetail  bool // Keyboard focus is on the detail pane
+	detailOffset int  // Scroll offset of the detail pane
+	detailFor    int  // Resource the detail offset applies to
+
+	chronological bool // Log tab shows diagnostics where they occurred, not at the end
+
+	reviewed          map[string]bool // Addresses marked as reviewed
+	reviewDir         string          // Where review marks are saved ("" disables saving)
+	reviewFingerprint string          // Plan fingerprint the saved marks were loaded for
+
+	sideBySide bool              // Updates and replaces shown as before/after columns
+	diffRows   map[int][]DiffRow // Diff rows by resource index, rebuilt with the lines
 
 	// Concurrency
 	streamChan chan StreamMsg     // Channel for receiving parsed content
 	cancelFunc context.CancelFunc // For signaling goroutine shutdown
+	killFunc   func()             // Kills the wrapped command
+	stopping   bool               // Interrupt sent, waiting for the command to exit
 
 	// Cached theme to avoid repeated allocations during rendering
 	cachedTheme *Theme
 
+	// User settings and the provider error classifier built from them
+	config           Config
+	cachedClassifier *Classifier
+
 	// Exit code tracking for error detection
 	exitCode int  // Exit code from terraform command (0 = success)
-	hasError bool // True if exitCode != 0, indicates execution failure
+	hasError bool // True if exitCode means the command failed
 }
 
 func (m *Model) theme() Theme {
@@ -283,13 +370,25 @@ func (m *Model) readInputStream(ctx context.Context, reader io.Reader) {
 	defer close(m.streamChan)
 
 	buf := make([]byte, 4096)
-	var lineBuffer string
 	var currentResource *ResourceChange
 	var diagLines []string
 	inResource := false
 	inDiagnostic := false
 	bracketDepth := 0
 	receivedContent := false
+	logCount := 0   // LogLines sent so far
+	lineLogID := -1 // Index of the LogLine sent for the current line, if any
+
+	// sendLog sends a log line, counting it so later redraws can refer to it
+	sendLog := func(l string) bool {
+		select {
+		case m.streamChan <- StreamMsg{LogLine: &l}:
+			logCount++
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
 
 	processLine := func(rawLine string) {
 		cleanLine := stripANSI(rawLine)
@@ -316,10 +415,7 @@ func (m *Model) readInputStream(ctx context.Context, reader io.Reader) {
 					// Fallback: preserve unrecognized content as log lines
 					for _, line := range diagLines {
 						if strings.TrimSpace(stripANSI(line)) != "" {
-							l := stripANSI(line)
-							select {
-							case m.streamChan <- StreamMsg{LogLine: &l}:
-							case <-ctx.Done():
+							if !sendLog(stripANSI(line)) {
 								return
 							}
 						}
@@ -344,10 +440,7 @@ func (m *Model) readInputStream(ctx context.Context, reader io.Reader) {
 					// This ensures NO information is lost, even for unrecognized formats
 					for _, line := range diagLines {
 						if strings.TrimSpace(stripANSI(line)) != "" {
-							l := stripANSI(line)
-							select {
-							case m.streamChan <- StreamMsg{LogLine: &l}:
-							case <-ctx.Done():
+							if !sendLog(stripANSI(line)) {
 								return
 							}
 						}
@@ -429,16 +522,24 @@ func (m *Model) readInputStream(ctx context.Context, reader io.Reader) {
 		}
 
 		// Generic log line
-		if strings.TrimSpace(cleanLine) != "" {
-			l := cleanLine
-			select {
-			case m.streamChan <- StreamMsg{LogLine: &l}:
-			case <-ctx.Done():
-				return
-			}
+		if strings.TrimSpace(cleanLine) != "" && sendLog(cleanLine) {
+			lineLogID = logCount - 1
 		}
 	}
 
+	// Output goes through a terminal emulator so progress redrawn with \r or
+	// cursor movement ends up as the lines a terminal would show
+	term := newLogTerminal(func(line string) int {
+		lineLogID = -1
+		processLine(line)
+		return lineLogID
+	}, func(id int, line string) {
+		select {
+		case m.streamChan <- StreamMsg{LogUpdate: &LogUpdate{Index: id, Line: stripANSI(line)}}:
+		case <-ctx.Done():
+		}
+	})
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -447,23 +548,19 @@ func (m *Model) readInputStream(ctx context.Context, reader io.Reader) {
 		}
 
 		n, err := reader.Read(buf)
-		if n > 0 {
-			chunk := string(buf[:n])
-			lineBuffer += chunk
-
-			// Process complete lines
-			for {
-				idx := strings.Index(lineBuffer, "\n")
-				if idx == -1 {
-					break
-				}
-				line := strings.TrimSuffix(lineBuffer[:idx], "\r")
-				lineBuffer = lineBuffer[idx+1:]
-				processLine(line)
+		if n > 0 && m.ptyFile != nil {
+			raw := append([]byte(nil), buf[:n]...)
+			select {
+			case m.streamChan <- StreamMsg{Output: raw}:
+			case <-ctx.Done():
+				return
 			}
+		}
+		if n > 0 {
+			term.Write(buf[:n])
 
 			// Check for prompt (no trailing newline)
-			cleanBuffer := stripANSI(lineBuffer)
+			cleanBuffer := stripANSI(term.partial())
 			if promptPattern.MatchString(cleanBuffer) {
 				p := strings.TrimSpace(cleanBuffer)
 				select {
@@ -478,11 +575,8 @@ func (m *Model) readInputStream(ctx context.Context, reader io.Reader) {
 		}
 	}
 
-	// Flush any remaining content in line buffer (last line without trailing newline)
-	if strings.TrimSpace(lineBuffer) != "" {
-		processLine(lineBuffer)
-		lineBuffer = ""
-	}
+	// Flush the rows still on screen (including a last line without trailing newline)
+	term.flush()
 
 	// Flush any pending diagnostic block (stream ended without closing ╵)
 	if inDiagnostic && len(diagLines) > 0 {
@@ -496,11 +590,7 @@ func (m *Model) readInputStream(ctx context.Context, reader io.Reader) {
 			// Fallback: preserve unrecognized diagnostic content as log lines
 			for _, line := range diagLines {
 				if strings.TrimSpace(stripANSI(line)) != "" {
-					l := stripANSI(line)
-					select {
-					case m.streamChan <- StreamMsg{LogLine: &l}:
-					case <-ctx.Done():
-					}
+					sendLog(stripANSI(line))
 				}
 			}
 		}
@@ -542,9 +632,8 @@ func tickCmd() tea.Cmd {
 // visibleHeight calculates the number of content lines visible in the viewport
 func (m *Model) visibleHeight() int {
 	h := m.height - headerFooterHeight
-	if m.prompt != "" {
-		h -= 2 // Reserve space for pinned prompt
-	}
+	h -= m.promptHeight()
+	h -= m.dialogHeight()
 	if h < minVisibleHeight {
 		h = minVisibleHeight
 	}
@@ -554,15 +643,50 @@ func (m *Model) visibleHeight() int {
 // rebuildLines reconstructs the display lines based on current expand state
 func (m *Model) rebuildLines() {
 	m.lines = nil
+	m.scanCrashes()
+	m.diffRows = make(map[int][]DiffRow)
+
+	switch m.tab {
+	case TabDiagnostics:
+		m.diagGroups = groupDiagnostics(m.diagnostics)
+		if len(m.diagGroups) == 0 {
+			m.appendTextLines("No errors or warnings")
+		}
+		for _, g := range m.diagGroups {
+			m.appendDiagnosticGroupLines(g)
+		}
+		return
+	case TabOutputs:
+		m.appendOutputLines()
+		return
+	case TabSummary:
+		m.appendSummaryLines()
+		return
+	}
 
-	if m.showLogs {
+	if m.tab == TabLog {
 		// LOG view: show all output including logs and diagnostics
-		// First show regular logs (terraform output in chronological order)
-		for i, log := range m.logs {
+		// First show regular logs (terraform output in chronological order).
+		// In the chronological layout each diagnostic is placed before the
+		// first log line that arrived after it.
+		m.diagGroups = groupDiagnostics(m.diagnostics)
+		next := 0
+		for i := 0; i < len(m.logs); i++ {
+			if m.chronological {
+				next = m.appendDiagnosticsBefore(next, i)
+			}
+
+			// Fold panic stack traces into a single collapsible block
+			if c := m.crashAt(i); c >= 0 {
+				m.appendCrashLines(c)
+				i = m.crashes[c].End - 1
+				continue
+			}
+
 			// Wrap log lines
 			// renderLogLine adds 2 spaces padding/cursor
 			// So we wrap at width - 2
-			wrapped := wrapText(log, m.width-2, 0)
+			wrapped := wrapText(m.logs[i], m.width-2, 0)
 			for _, w := range wrapped {
 				m.lines = append(m.lines, Line{
 					Type:    LineTypeLog,
@@ -572,36 +696,16 @@ func (m *Model) rebuildLines() {
 			}
 		}
 
-		// Then show diagnostics (errors/warnings) at the end where they're most visible
-		// This ensures errors appear after the normal terraform output
-		for i, diag := range m.diagnostics {
-			// Wrap summary (accounting for 4 chars prefix: "▸ ✗ ")
-			wrappedSummary := wrapText(diag.Summary, m.width-4, 0)
-			for wIdx, summaryLine := range wrappedSummary {
-				m.lines = append(m.lines, Line{
-					Type:        LineTypeDiagnostic,
-					DiagIdx:     i,
-					ResourceIdx: -1,
-					AttrIdx:     wIdx,
-					Content:     summaryLine,
-				})
-			}
+		if m.chronological {
+			m.appendDiagnosticsBefore(next, math.MaxInt)
+			return
+		}
 
-			// Add detail lines with proper diagnostic detail formatting
-			// This preserves guide colors (│, ├, ─, ╵) and underline markers (^, ~)
-			for j, detail := range diag.Detail {
-				// Wrap diagnostic details (accounting for 4 spaces padding in render)
-				wrapped := wrapText(detail.Content, m.width-4, 0)
-				for _, w := range wrapped {
-					m.lines = append(m.lines, Line{
-						Type:        LineTypeDiagnosticDetail,
-						DiagIdx:     i,
-						ResourceIdx: -1,
-						AttrIdx:     j,
-						Content:     w,
-					})
-				}
-			}
+		// Then show diagnostics (errors/warnings) at the end where they're most visible
+		// This ensures errors appear after the normal terraform output.
+		// Repeated diagnostics are grouped into a single entry with their occurrences.
+		for _, g := range m.diagGroups {
+			m.appendDiagnosticGroupLines(g)
 		}
 		return
 	}
@@ -611,13 +715,19 @@ func (m *Model) rebuildLines() {
 	// This ensures clear separation: PLAN = resource changes, LOG = errors/output
 
 	for i, rc := range m.resources {
+		if !m.resourceVisible(rc) {
+			continue
+		}
 		m.lines = append(m.lines, Line{
 			Type:        LineTypeResource,
 			ResourceIdx: i,
 			DiagIdx:     -1,
 			AttrIdx:     -1,
 		})
-		if rc.Expanded {
+		// In the split layout attributes are shown in the detail pane instead
+		if rc.Expanded && !m.splitActive() && m.diffSideBySide(rc) {
+			m.lines = append(m.lines, m.diffLines(i, m.width)...)
+		} else if rc.Expanded && !m.splitActive() {
 			for j, attr := range rc.Attributes {
 				// Wrap attributes
 				// Indentation is preserved in attr string, so we use full width
@@ -718,9 +828,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						{Content: "Or use interactive mode: terraui terraform plan"},
 					},
 					Expanded: true,
+					LogPos:   len(m.logs),
 				}
 				m.diagnostics = append(m.diagnostics, warning)
 			}
+			m.restoreReviews()
 			m.needsSync = true
 			return m, nil
 		}
@@ -736,25 +848,39 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			if !hasErrors {
-				m.showLogs = false
+				m.autoSwitchTab(TabPlan)
 			}
 			m.needsSync = true
 		}
 		if msg.Diagnostic != nil {
-			m.diagnostics = append(m.diagnostics, *msg.Diagnostic)
-			// Fix timing gap: if an error occurs, switch to LOG view immediately
-			// so the user sees it, rather than waiting for exit code.
+			diag := *msg.Diagnostic
+			diag.LogPos = len(m.logs)
+			m.redactDiagnostic(&diag)
+			m.classifyDiagnostic(&diag)
+			m.diagnostics = append(m.diagnostics, diag)
+			// Fix timing gap: if an error occurs, switch to the Diagnostics tab
+			// immediately so the user sees it, rather than waiting for exit code.
 			if msg.Diagnostic.Severity == "error" {
-				m.showLogs = true
+				m.autoSwitchTab(TabDiagnostics)
 			}
 			m.needsSync = true
 		}
+		if msg.Output != nil && m.screen != nil {
+			m.screen.Write(m.redactOutput(msg.Output))
+		}
 		if msg.LogLine != nil {
-			m.logs = append(m.logs, *msg.LogLine)
+			m.streamLogs = append(m.streamLogs, len(m.logs))
+			m.logs = append(m.logs, m.redact(*msg.LogLine))
+			m.needsSync = true
+		}
+		if u := msg.LogUpdate; u != nil && u.Index < len(m.streamLogs) {
+			m.logs[m.streamLogs[u.Index]] = m.redact(u.Line)
 			m.needsSync = true
 		}
 		if msg.Prompt != nil {
-			m.prompt = *msg.Prompt
+			m.setPrompt(*msg.Prompt)
+			m.restoreReviews()
+			m.leaveGuardedPassthrough()
 			m.needsSync = true
 		}
 		return m, m.waitForStreamMsg()
@@ -762,9 +888,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case exitCodeMsg:
 		m.exitCode = msg.exitCode
 		m.hasError = msg.hasError
-		// Auto-switch to LOG view when there's an error
+		m.stopping = false
+		// Auto-switch to the errors when there are any, else the log
 		if m.hasError {
-			m.showLogs = true
+			if len(m.diagnostics) > 0 {
+				m.autoSwitchTab(TabDiagnostics)
+			} else {
+				m.autoSwitchTab(TabLog)
+			}
+		}
+		m.needsSync = true
+		return m, nil
+
+	case editorFinishedMsg:
+		if msg.err != nil {
+			m.logs = append(m.logs, fmt.Sprintf("Failed to open editor: %v", msg.err))
+			m.needsSync = true
+		}
+		return m, nil
+
+	case forceUnlockFinishedMsg:
+		if msg.err != nil {
+			m.logs = append(m.logs, fmt.Sprintf("force-unlock %s failed: %v", msg.id, msg.err))
+		} else {
+			m.logs = append(m.logs, fmt.Sprintf("force-unlock %s succeeded", msg.id))
 		}
 		m.needsSync = true
 		return m, nil
@@ -773,6 +920,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.ready = true
+		if m.screen != nil {
+			m.screen.resize(msg.Width, msg.Height-1) // Below the banner line
+		}
+		m.resizePTY()
 		m.needsSync = true
 		return m, nil
 
@@ -789,6 +940,38 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // handleMouseMsg processes mouse events
 func (m Model) handleMouseMsg(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	m.autoScroll = false
+	if m.palette != nil || m.passthrough {
+		return m, nil
+	}
+
+	// Clicking the tab bar switches tabs
+	if msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress && msg.Y == 0 {
+		if tab, ok := m.tabAt(msg.X); ok {
+			m.switchTab(tab)
+		}
+		return m, nil
+	}
+
+	// In the split layout the wheel scrolls the focused pane and a click
+	// picks which pane has focus
+	if m.splitActive() {
+		listWidth, _ := m.splitWidths()
+		if msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress {
+			m.focusDetail = msg.X > listWidth
+			if m.focusDetail {
+				return m, nil
+			}
+		}
+		if m.focusDetail {
+			switch msg.Button {
+			case tea.MouseButtonWheelUp:
+				m.scrollDetail(-mouseScrollLines)
+			case tea.MouseButtonWheelDown:
+				m.scrollDetail(mouseScrollLines)
+			}
+			return m, nil
+		}
+	}
 
 	switch msg.Button {
 	case tea.MouseButtonWheelUp:
@@ -825,30 +1008,69 @@ func (m Model) handleMouseMsg(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	m.autoScroll = false
 
+	if m.passthrough {
+		return m.handlePassthroughKey(msg)
+	}
+	if m.dialog != nil {
+		return m.handleDialogKey(msg)
+	}
+	if m.palette != nil {
+		return m.handlePaletteKey(msg)
+	}
+	if m.searching {
+		return m.handleSearchInput(msg)
+	}
+	if m.editingFilter {
+		return m.handleAddressFilterInput(msg)
+	}
+	if m.pendingKey == "f" {
+		m.pendingKey = ""
+		if m.tab == TabPlan {
+			m.handleFilterKey(msg.String())
+		}
+		return m, nil
+	}
+
 	// Input mode: handle typing
 	if m.inputMode && m.ptyFile != nil {
 		return m.handleInputMode(msg)
 	}
 
+	// y/n answer Terraform's approval question directly
+	if m.handleApprovalKey(msg.String()) {
+		return m, nil
+	}
+
+	// Navigation keys scroll the detail pane when it has focus
+	if m.handleDetailKey(msg.String()) {
+		return m, nil
+	}
+
 	// Normal navigation mode
 	switch msg.String() {
-	case "q", "ctrl+c":
-		if m.cancelFunc != nil {
-			m.cancelFunc()
-		}
-		return m, tea.Quit
+	case "q":
+		return m, m.requestQuit()
+
+	case "ctrl+c":
+		return m, m.interrupt()
 
 	case "i":
 		if m.ptyFile != nil {
 			m.inputMode = true
 		}
 
+	case "T":
+		m.togglePassthrough()
+
 	case "l", "L":
-		m.showLogs = !m.showLogs
-		m.rebuildLines()
-		m.cursor = 0
-		m.offset = 0
-		m.autoScroll = false
+		if m.tab == TabLog {
+			m.switchTab(TabPlan)
+		} else {
+			m.switchTab(TabLog)
+		}
+
+	case "1", "2", "3", "4", "5":
+		m.switchTab(Tab(msg.Runes[0] - '1'))
 
 	case "m":
 		m.toggleRenderingMode()
@@ -866,7 +1088,31 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	case "enter", " ":
-		m.toggleExpand(m.cursor)
+		if m.splitActive() {
+			m.toggleSplitFocus()
+		} else {
+			m.toggleExpand(m.cursor)
+		}
+
+	case "v":
+		if m.tab == TabPlan {
+			m.toggleSplitPane()
+		}
+
+	case "tab":
+		m.toggleSplitFocus()
+
+	case "d":
+		m.toggleSideBySide()
+
+	case "t":
+		m.toggleLogLayout()
+
+	case "x":
+		m.toggleReviewed()
+
+	case "u":
+		m.jumpToUnreviewed()
 
 	case "pgup", "ctrl+u":
 		m.cursor -= m.height / 2
@@ -886,6 +1132,56 @@ func (m Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.cursor = len(m.lines) - 1
 		m.ensureCursorVisible()
 
+	case "s":
+		m.toggleSource(m.cursor)
+
+	case "o":
+		return m, m.openInEditor()
+
+	case "h":
+		m.toggleHint(m.cursor)
+
+	case "f":
+		// Action filters only apply to the Plan view
+		if m.tab == TabPlan {
+			m.pendingKey = "f"
+		}
+
+	case "ctrl+p":
+		if len(m.resources) > 0 {
+			m.openPalette()
+		}
+
+	case "F":
+		if m.tab == TabPlan {
+			m.startAddressFilter()
+		}
+
+	case "/":
+		m.startSearch(false)
+
+	case "?":
+		m.startSearch(true)
+
+	case "n":
+		if m.search != nil {
+			m.jumpToMatch(m.search.backward)
+		}
+
+	case "N":
+		if m.search != nil {
+			m.jumpToMatch(!m.search.backward)
+		}
+
+	case "esc":
+		m.search = nil
+
+	case "R":
+		return m, m.rerunWithLockTimeout()
+
+	case "U":
+		m.confirmForceUnlock()
+
 	case "e":
 		m.expandAll(true)
 
@@ -903,54 +1199,62 @@ func (m Model) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.inputMode = false
 
 	case tea.KeyCtrlC:
-		if _, err := m.ptyFile.Write([]byte{3}); err != nil {
-			// Log error but continue - user wants to quit anyway
-		}
-		if m.cancelFunc != nil {
-			m.cancelFunc()
-		}
-		return m, tea.Quit
-
-	case tea.KeyBackspace, tea.KeyDelete:
-		if len(m.userInput) > 0 {
-			m.userInput = m.userInput[:len(m.userInput)-1]
-		}
-
-	case tea.KeyRunes:
-		m.userInput += string(msg.Runes)
+		m.inputMode = false
+		return m, m.interrupt()
 
-	case tea.KeySpace:
-		m.userInput += " "
+	case tea.KeyCtrlS:
+		m.toggleMask()
 
 	case tea.KeyEnter:
-		payload := m.userInput + "\n"
-		if m.ptyFile != nil {
-			if _, err := m.ptyFile.Write([]byte(payload)); err != nil {
-				// PTY write failed - log to stderr for debugging
-				fmt.Fprintf(os.Stderr, "[ERROR] Failed to write to PTY: %v\n", err)
-			}
-		} else {
-			fmt.Fprintf(os.Stderr, "[ERROR] PTY file is nil, cannot write input\n")
+		// Masked answers are kept out of the history
+		answer := m.input.submit(!m.promptCtx.Masked)
+		// Approving destroys or replaces needs a second, typed confirmation
+		if m.needsGuard(answer) {
+			m.confirmDestructiveApply()
+			return m, nil
 		}
-		m.userInput = ""
-		m.prompt = ""
-		m.inputMode = false
-		m.showLogs = true
-		m.autoScroll = true
-		m.rebuildLines()
-		m.needsSync = true // Force TUI to redraw with new logs
+		m.submitInput(answer)
+
+	default:
+		m.input.handleKey(msg)
 	}
 
 	return m, nil
 }
 
+// submitInput writes an answer to the PTY and follows the output in the Log tab
+func (m *Model) submitInput(answer string) {
+	m.rememberSecret(answer)
+	payload := answer + "\n"
+	if m.ptyFile != nil {
+		if _, err := m.ptyFile.Write([]byte(payload)); err != nil {
+			// PTY write failed - log to stderr for debugging
+			fmt.Fprintf(os.Stderr, "[ERROR] Failed to write to PTY: %v\n", err)
+		}
+	} else {
+		fmt.Fprintf(os.Stderr, "[ERROR] PTY file is nil, cannot write input\n")
+	}
+	m.input.clear()
+	m.prompt = ""
+	m.promptCtx = promptContext{}
+	m.inputMode = false
+	m.switchTab(TabLog)
+	m.autoScroll = true
+	m.rebuildLines()
+	m.needsSync = true // Force TUI to redraw with new logs
+}
+
 // toggleExpand toggles the expanded state of a resource or diagnostic at lineIdx
 func (m *Model) toggleExpand(lineIdx int) {
-	if lineIdx < 0 || lineIdx >= len(m.lines) || m.showLogs {
+	if lineIdx < 0 || lineIdx >= len(m.lines) {
 		return
 	}
 
 	line := m.lines[lineIdx]
+	if line.Type == LineTypeCrash {
+		m.toggleCrash(line.DiagIdx)
+		return
+	}
 	switch line.Type {
 	case LineTypeResource:
 		if line.ResourceIdx >= 0 && line.ResourceIdx < len(m.resources) {
@@ -959,27 +1263,46 @@ func (m *Model) toggleExpand(lineIdx int) {
 			m.clampCursor()
 			m.clampOffset()
 		}
-	case LineTypeDiagnostic:
+	case LineTypeDiagnostic, LineTypeDiagnosticDetail, LineTypeOccurrence:
+		// The "more similar elsewhere" line has nothing to expand
+		if line.Type == LineTypeOccurrence && line.AttrIdx < 0 {
+			return
+		}
 		if line.DiagIdx >= 0 && line.DiagIdx < len(m.diagnostics) {
 			m.diagnostics[line.DiagIdx].Expanded = !m.diagnostics[line.DiagIdx].Expanded
 			m.rebuildLines()
+			// Collapsing from a detail line leaves the cursor on the summary,
+			// or on the location line of a later occurrence
+			for i, l := range m.lines {
+				if (l.Type == LineTypeDiagnostic || l.Type == LineTypeOccurrence) && l.DiagIdx == line.DiagIdx {
+					m.cursor = i
+					break
+				}
+			}
 			m.clampCursor()
-			m.clampOffset()
+			m.ensureCursorVisible()
 		}
 	}
 }
 
-// expandAll sets the expanded state of all resources and diagnostics
+// expandAll sets the expanded state of the resources in the Plan tab or the
+// diagnostics in the Diagnostics and Log tabs
 func (m *Model) expandAll(expanded bool) {
-	if m.showLogs {
+	switch m.tab {
+	case TabPlan:
+		// Only resources visible through the filters are affected
+		for i := range m.resources {
+			if m.resourceVisible(m.resources[i]) {
+				m.resources[i].Expanded = expanded
+			}
+		}
+	case TabDiagnostics, TabLog:
+		for i := range m.diagnostics {
+			m.diagnostics[i].Expanded = expanded
+		}
+	default:
 		return
 	}
-	for i := range m.resources {
-		m.resources[i].Expanded = expanded
-	}
-	for i := range m.diagnostics {
-		m.diagnostics[i].Expanded = expanded
-	}
 	m.rebuildLines()
 	m.clampCursor()
 	m.clampOffset()
@@ -990,6 +1313,9 @@ func (m Model) View() string {
 	if !m.ready {
 		return "Loading..."
 	}
+	if m.passthrough {
+		return m.renderPassthrough()
+	}
 
 	vh := m.visibleHeight()
 	startLine := m.offset
@@ -1012,15 +1338,35 @@ func (m Model) View() string {
 	output.WriteString(m.renderHeader())
 	output.WriteString("\n\n")
 
+	// The palette is drawn over the content lines
+	if m.palette != nil {
+		output.WriteString(m.renderPalette(vh))
+		output.WriteString("\n")
+		output.WriteString(m.renderPaletteFooter())
+		return output.String()
+	}
+
 	// Scroll indicator (top)
 	if startLine > 0 {
 		output.WriteString(m.theme().Dim.Render(fmt.Sprintf("  ↑ %d more lines above\n", startLine)))
 	}
 
 	// Content lines
-	for i := startLine; i < endLine; i++ {
-		output.WriteString(m.renderLine(i))
-		output.WriteString("\n")
+	searchPattern := m.activeSearchPattern()
+	render := func(i int) string {
+		rendered := m.renderLine(i)
+		if searchPattern != nil {
+			rendered = highlightMatches(rendered, searchPattern)
+		}
+		return rendered
+	}
+	if m.splitActive() {
+		output.WriteString(m.renderSplitPane(startLine, endLine, vh, render))
+	} else {
+		for i := startLine; i < endLine; i++ {
+			output.WriteString(render(i))
+			output.WriteString("\n")
+		}
 	}
 
 	// Scroll indicator (bottom)
@@ -1034,8 +1380,19 @@ func (m Model) View() string {
 		output.WriteString(m.renderPrompt())
 	}
 
+	// Confirmation dialog
+	if m.dialog != nil {
+		output.WriteString("\n")
+		output.WriteString(m.renderDialog())
+	}
+
 	// Footer
 	output.WriteString("\n")
+	if m.editingFilter {
+		output.WriteString(m.renderAddressFilterBar() + "  ")
+	} else if m.searching || m.search != nil {
+		output.WriteString(m.renderSearchBar() + "  ")
+	}
 	output.WriteString(m.renderFooter())
 
 	return output.String()
@@ -1044,22 +1401,36 @@ func (m Model) View() string {
 // renderHeader renders the header bar with mode, status, and controls
 func (m Model) renderHeader() string {
 	t := m.theme()
-	var header string
+	// The tab bar comes first so clicks can be mapped to tabs by column
+	header := m.renderTabBar()
 	if m.inputMode {
-		header = t.InputMode.Render("INPUT") + " " + t.Dim.Render("Interactive Mode")
-	} else if m.showLogs {
-		// Show LOGS (ERROR) in red when there's an error
-		if m.hasError {
-			header = t.HeaderError.Render("LOGS (ERROR)") + " " + t.Dim.Render(fmt.Sprintf("Exit Code: %d", m.exitCode))
-		} else {
-			header = t.HeaderLog.Render("LOGS") + " " + t.Dim.Render("Terraform Output")
+		header += " " + t.InputMode.Render("INPUT") + " " + t.Dim.Render("Interactive Mode")
+	}
+	if m.hasError {
+		header += " " + t.HeaderError.Render("ERROR") + " " + t.Dim.Render(fmt.Sprintf("Exit Code: %d", m.exitCode))
+	} else if m.changesPresent() {
+		header += " " + t.Warning.Render("CHANGES PRESENT") + " " + t.Dim.Render(fmt.Sprintf("Exit Code: %d", m.exitCode))
+	}
+
+	if len(m.crashes) > 0 {
+		header += " " + t.HeaderError.Render("✗ CRASHED")
+	}
+	if m.tab == TabPlan {
+		if label := m.filterLabel(); label != "" {
+			header += " " + t.Warning.Render(label)
 		}
-	} else {
-		header = t.HeaderPlan.Render("PLAN") + " " + t.Dim.Render("Terraform Viewer")
+		if progress := m.reviewProgress(); progress != "" {
+			header += " " + t.Create.Render(progress)
+		}
+	}
+	if m.pendingKey == "f" {
+		header += " " + t.Prompt.Render("f: c/u/d/r/i action  !:dangerous  a:all")
 	}
 
 	var status string
-	if m.prompt != "" {
+	if m.stopping {
+		status = t.Warning.Render(" ● Stopping… (press again to force)")
+	} else if m.prompt != "" {
 		status = t.Warning.Render(" ● WAITING FOR INPUT")
 	} else if !m.done {
 		status = t.Dim.Render(" ● Live")
@@ -1067,12 +1438,14 @@ func (m Model) renderHeader() string {
 		status = t.Dim.Render(" ● Done")
 	}
 
-	controls := t.Dim.Render(" ↑↓:navigate  q:quit  L:mode  m:toggle colors")
+	controls := t.Dim.Render(" ↑↓:navigate  q:quit  1-5:tabs  m:toggle colors")
 	if m.ptyFile != nil {
 		if m.inputMode {
 			controls += t.Dim.Render("  Esc:exit input")
+		} else if m.approvalKeysActive() {
+			controls += t.Dim.Render("  y:approve  n:reject  i:enter input  T:terminal")
 		} else {
-			controls += t.Dim.Render("  i:enter input")
+			controls += t.Dim.Render("  i:enter input  T:terminal")
 		}
 	}
 
@@ -1099,6 +1472,18 @@ func (m Model) renderLine(idx int) string {
 		return m.renderResourceLine(line.ResourceIdx, isSelected)
 	case LineTypeAttribute:
 		return m.renderAttributeLine(line, isSelected)
+	case LineTypeSource:
+		return m.renderSourceLine(line, isSelected)
+	case LineTypeOccurrence:
+		return m.renderOccurrenceLine(line, isSelected)
+	case LineTypeHint:
+		return m.renderHintLine(line, isSelected)
+	case LineTypeCrash:
+		return m.renderCrashLine(line, isSelected)
+	case LineTypeLockCard:
+		return m.renderLockCardLine(line, isSelected)
+	case LineTypeDiff:
+		return m.renderDiffLine(line, isSelected, m.width)
 	}
 
 	return ""
@@ -1171,6 +1556,12 @@ func (m Model) renderDiagnosticLine(line Line, isSelected bool) string {
 	var prefix string
 	if line.AttrIdx <= 0 {
 		prefix = fmt.Sprintf("%s %s ", expandIcon, symbol)
+		if count := m.groupTotal(line.DiagIdx); count > 1 {
+			prefix += fmt.Sprintf("×%d ", count)
+		}
+		if diag.Category != "" {
+			prefix += "[" + diag.Category + "] "
+		}
 
 		// Add "Error:" or "Warning:" prefix if it's the first line of summary
 		// We re-add it because parser stripped it, but we want to render it with style.
@@ -1277,6 +1668,7 @@ func (m Model) renderResourceLine(resIdx int, isSelected bool) string {
 	if rc.Expanded {
 		expandIcon = "▾"
 	}
+	expandIcon += m.reviewMark(rc.Address)
 
 	// Format content based on mode
 	var content string
@@ -1365,22 +1757,23 @@ func (m Model) renderAttributeLine(line Line, isSelected bool) string {
 	return m.styleAttributeMinimal(content, original)
 }
 
-// renderPrompt renders the pinned prompt with optional input cursor
-func (m Model) renderPrompt() string {
-	t := m.theme()
-	promptLine := t.Prompt.Render(">> " + m.prompt)
-	if m.inputMode {
-		promptLine += " " + t.Create.Render(m.userInput) + t.Dim.Render("█")
-	}
-	return promptLine
-}
-
 // renderFooter renders the summary footer
 func (m Model) renderFooter() string {
-	if m.showLogs {
-		return m.theme().Dim.Render(fmt.Sprintf("%d lines", len(m.lines)))
+	if m.tab != TabPlan {
+		footer := fmt.Sprintf("%d lines", len(m.lines))
+		if m.tab == TabLog && m.chronological {
+			footer += "  ·  chronological"
+		}
+		if unique, total := diagnosticCounts(groupDiagnostics(m.diagnostics)); total > 0 {
+			footer += fmt.Sprintf("  ·  %d unique / %d total diagnostics", unique, total)
+		}
+		return m.theme().Dim.Render(footer)
 	}
-	return m.getSummary(m.resources, m.diagnostics)
+	summary := m.getSummary(m.visibleResources(), m.diagnostics)
+	if label := m.filterCountLabel(); label != "" {
+		summary += "  " + m.theme().Dim.Render(label)
+	}
+	return summary
 }
 
 // styleAttributeMinimal styles an attribute with minimal color (only symbols)
@@ -1727,36 +2120,127 @@ func parseDiagnosticBlock(richLines []string) *Diagnostic {
 }
 
 func main() {
+	opts, args, err := parseArgs(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n%s", err, usageText)
+		os.Exit(exitUsage)
+	}
+	if opts.help {
+		fmt.Print(usageText)
+		return
+	}
+	if opts.version {
+		fmt.Println("terraui " + version)
+		return
+	}
+
+	configPath := defaultConfigPath()
+	if opts.configPath != "" {
+		// Unlike the default config, a config asked for must exist
+		if _, err := os.Stat(opts.configPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		configPath = opts.configPath
+	}
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	classifier, err := newClassifier(cfg.ClassifierRules)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// A session may ask to be re-run with different arguments
+	// (e.g. adding -lock-timeout after a state lock error)
+	for {
+		final, err := runSession(args, opts, cfg, classifier)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if final.rerunArgs == nil {
+			if opts.exportPath != "" {
+				if err := final.exportOutput(opts.exportPath); err != nil {
+					fmt.Fprintf(os.Stderr, "Error exporting output: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			os.Exit(final.processExitCode())
+		}
+		args = final.rerunArgs
+	}
+}
+
+// runSession runs the UI once, wrapping args in a PTY if given or reading
+// stdin otherwise, and returns the final model.
+func runSession(args []string, opts cliOptions, cfg Config, classifier *Classifier) (Model, error) {
 	var ptyFile *os.File
 	var cmd *exec.Cmd
+	var reader io.Reader = os.Stdin
+
+	// Pipe mode from a file: terraui --input plan.txt
+	if opts.inputPath != "" {
+		f, err := os.Open(opts.inputPath)
+		if err != nil {
+			return Model{}, fmt.Errorf("opening input: %w", err)
+		}
+		defer f.Close()
+		reader = f
+	}
 
 	// Interactive mode: terraui terraform apply ...
-	if len(os.Args) > 1 {
-		cmd = exec.Command(os.Args[1], os.Args[2:]...)
+	if len(args) > 0 {
+		cmd = exec.Command(args[0], args[1:]...)
 		var err error
-		ptyFile, err = pty.Start(cmd)
+		// Start at the terminal's size; later resizes are forwarded by the UI
+		if size, sizeErr := pty.GetsizeFull(os.Stdout); sizeErr == nil {
+			ptyFile, err = pty.StartWithSize(cmd, size)
+		} else {
+			ptyFile, err = pty.Start(cmd)
+		}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error starting PTY: %v\n", err)
-			os.Exit(1)
+			return Model{}, fmt.Errorf("starting PTY: %w", err)
 		}
 	}
 
 	// Create context for cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 
+	// Start in the Log tab unless another view was asked for
+	tab := TabLog
+	if opts.viewSet {
+		tab = opts.view
+	}
+
 	// Create model with buffered channel
 	m := Model{
-		showLogs:      true,
+		tab:           tab,
+		fixedView:     opts.viewSet,
 		autoScroll:    true,
-		renderingMode: RenderingModeDashboard,
+		renderingMode: opts.mode,
+		command:       args,
 		ptyFile:       ptyFile,
+		screen:        newVTScreenFor(ptyFile),
 		streamChan:    make(chan StreamMsg, streamBufferSize),
 		exitCode:      -1, // -1 means not yet set
 		cancelFunc:    cancel,
+		killFunc: func() {
+			if cmd != nil && cmd.Process != nil {
+				cmd.Process.Kill()
+			}
+		},
+
+		config:           cfg,
+		cachedClassifier: classifier,
+		reviewDir:        defaultReviewDir(cfg),
+		addrFilter:       opts.addrFilter,
 	}
 
 	// Start the input reading goroutine
-	var reader io.Reader = os.Stdin
 	if m.ptyFile != nil {
 		reader = m.ptyFile
 	}
@@ -1770,6 +2254,7 @@ func main() {
 	// Only the Wait goroutine calls cmd.Wait() to avoid double-wait issues.
 	exitChan := make(chan int, 1)
 	cmdDone := make(chan struct{})
+	commandExit := -1 // Set before cmdDone is closed
 
 	// Start the sole goroutine that waits for command completion
 	if cmd != nil {
@@ -1784,6 +2269,7 @@ func main() {
 					exitCode = 1
 				}
 			}
+			commandExit = exitCode
 			exitChan <- exitCode
 		}()
 	} else {
@@ -1812,26 +2298,47 @@ func main() {
 		}
 	}
 
-	// Start signal handler
+	// Start signal handler; it stops listening when the session ends
+	sessionDone := make(chan struct{})
+	defer close(sessionDone)
+	defer signal.Stop(sigChan)
 	go func() {
-		<-sigChan
-		cleanup()
-		os.Exit(0)
+		select {
+		case sig := <-sigChan:
+			// Exit with the command's code if it already exited, else as a
+			// shell would for a process stopped by the signal
+			code := signalExitCode(sig)
+			select {
+			case <-cmdDone:
+				if commandExit >= 0 {
+					code = commandExit
+				}
+			default:
+			}
+			cleanup()
+			os.Exit(code)
+		case <-sessionDone:
+		}
 	}()
 
-	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	programOpts := []tea.ProgramOption{tea.WithAltScreen()}
+	if !opts.noMouse {
+		programOpts = append(programOpts, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(m, programOpts...)
 
 	// Goroutine to send exit code to model once available
 	go func() {
 		exitCode := <-exitChan
-		p.Send(exitCodeMsg{exitCode: exitCode, hasError: exitCode != 0})
+		p.Send(exitCodeMsg{exitCode: exitCode, hasError: commandFailed(args, exitCode)})
 	}()
 
 	// Ensure cleanup on normal exit
 	defer cleanup()
 
-	if _, err := p.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	final, err := p.Run()
+	if err != nil {
+		return Model{}, err
 	}
+	return final.(Model), nil
 }
@@ -182,9 +182,9 @@ func TestHighContrastPalette(t *testing.T) {
 
 func TestLogWrapping(t *testing.T) {
 	m := &Model{
-		width:    10,
-		showLogs: true,
-		logs:     []string{"1234567890"}, // Length 10
+		width: 10,
+		tab:   TabLog,
+		logs:  []string{"1234567890"}, // Length 10
 	}
 
 	m.rebuildLines()
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Fuzzy match scoring: matched characters score a point, with bonuses for
+// runs of consecutive characters and for matches at the start of an address
+// segment, and a small penalty for each skipped character.
+const (
+	fuzzyConsecutiveBonus = 5
+	fuzzyBoundaryBonus    = 8
+	fuzzyGapPenalty       = 1
+)
+
+// paletteMatch is a resource matching the palette query
+type paletteMatch struct {
+	resourceIdx int
+	score       int
+	positions   []int // Rune offsets of the matched characters in the address
+}
+
+// palette is the "go to resource" overlay
+type palette struct {
+	query    string
+	selected int
+	matches  []paletteMatch
+}
+
+// isSegmentBoundary reports whether a rune following prev starts a new part
+// of an address
+func isSegmentBoundary(prev rune) bool {
+	switch prev {
+	case '.', '_', '-', '[', '"', '/':
+		return true
+	}
+	return false
+}
+
+// fuzzyMatch reports whether every rune of query appears in target in order,
+// case-insensitively, and scores the match. Matching is greedy, except that
+// the first rune prefers the start of an address segment.
+func fuzzyMatch(query, target string) (int, []int, bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(target))
+	if len(q) == 0 {
+		return 0, nil, true
+	}
+
+	positions := make([]int, 0, len(q))
+	score := 0
+	ti := 0
+	for qi, qr := range q {
+		found := -1
+		for j := ti; j < len(t); j++ {
+			if t[j] != qr {
+				continue
+			}
+			if found < 0 {
+				found = j
+			}
+			// The first query rune prefers a segment start over an earlier mid-word hit
+			if qi == 0 && (j == 0 || isSegmentBoundary(t[j-1])) {
+				found = j
+				break
+			}
+			if qi > 0 {
+				break
+			}
+		}
+		if found < 0 {
+			return 0, nil, false
+		}
+
+		score++
+		if found == 0 || isSegmentBoundary(t[found-1]) {
+			score += fuzzyBoundaryBonus
+		}
+		if len(positions) > 0 {
+			if gap := found - positions[len(positions)-1] - 1; gap == 0 {
+				score += fuzzyConsecutiveBonus
+			} else {
+				score -= gap * fuzzyGapPenalty
+			}
+		}
+		positions = append(positions, found)
+		ti = found + 1
+	}
+	return score, positions, true
+}
+
+// openPalette shows the palette with every resource listed
+func (m *Model) openPalette() {
+	m.palette = &palette{}
+	m.updatePaletteMatches()
+}
+
+// updatePaletteMatches re-runs the query over all resource addresses, best
+// matches first and ties in plan order.
+func (m *Model) updatePaletteMatches() {
+	p := m.palette
+	p.matches = p.matches[:0]
+	for i, rc := range m.resources {
+		if score, positions, ok := fuzzyMatch(p.query, rc.Address); ok {
+			p.matches = append(p.matches, paletteMatch{resourceIdx: i, score: score, positions: positions})
+		}
+	}
+	sort.SliceStable(p.matches, func(a, b int) bool {
+		return p.matches[a].score > p.matches[b].score
+	})
+	p.selected = 0
+}
+
+// handlePaletteKey processes keyboard input while the palette is open
+func (m Model) handlePaletteKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.palette
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.palette = nil
+
+	case "enter":
+		m.palette = nil
+		if p.selected < len(p.matches) {
+			m.gotoResource(p.matches[p.selected].resourceIdx)
+		}
+
+	case "up", "ctrl+p", "ctrl+k":
+		if p.selected > 0 {
+			p.selected--
+		}
+
+	case "down", "ctrl+n", "ctrl+j":
+		if p.selected < len(p.matches)-1 {
+			p.selected++
+		}
+
+	case "backspace", "delete":
+		if r := []rune(p.query); len(r) > 0 {
+			p.query = string(r[:len(r)-1])
+			m.updatePaletteMatches()
+		}
+
+	case "ctrl+u":
+		p.query = ""
+		m.updatePaletteMatches()
+
+	default:
+		switch msg.Type {
+		case tea.KeyRunes:
+			p.query += string(msg.Runes)
+			m.updatePaletteMatches()
+		case tea.KeySpace:
+			p.query += " "
+			m.updatePaletteMatches()
+		}
+	}
+	return m, nil
+}
+
+// gotoResource switches to the Plan view, expands the resource and centers
+// it. Filters hiding the resource are cleared so it can be shown.
+func (m *Model) gotoResource(resIdx int) {
+	m.switchTab(TabPlan)
+	if !m.resourceVisible(m.resources[resIdx]) {
+		m.actionFilter = nil
+		m.addrFilter = addressFilter{}
+	}
+	m.resources[resIdx].Expanded = true
+	m.rebuildLines()
+
+	for i, line := range m.lines {
+		if line.Type == LineTypeResource && line.ResourceIdx == resIdx {
+			m.cursor = i
+			break
+		}
+	}
+	m.centerCursor()
+}
+
+// centerCursor scrolls so the cursor line is in the middle of the viewport
+func (m *Model) centerCursor() {
+	m.offset = m.cursor - m.visibleHeight()/2
+	m.clampOffset()
+}
+
+// renderPalette renders the palette in place of the content lines, using at
+// most height lines.
+func (m Model) renderPalette(height int) string {
+	t := m.theme()
+	p := m.palette
+
+	var b strings.Builder
+	b.WriteString(t.Prompt.Render("Go to resource: ") + t.Default.Render(p.query) + t.Dim.Render("█"))
+	b.WriteString(t.Dim.Render(fmt.Sprintf("  %d/%d", len(p.matches), len(m.resources))))
+	b.WriteString("\n")
+
+	rows := height - 1
+	if rows < 1 {
+		rows = 1
+	}
+	// Scroll the list so the selection stays in view
+	start := 0
+	if p.selected >= rows {
+		start = p.selected - rows + 1
+	}
+	for i := start; i < len(p.matches) && i < start+rows; i++ {
+		match := p.matches[i]
+		rc := m.resources[match.resourceIdx]
+		symbol := m.getStyleForAction(rc.Action).Render(getSymbol(rc.Action))
+		address := highlightPositions(rc.Address, match.positions, t.Prompt.Render, t.Default.Render)
+		if i == p.selected {
+			b.WriteString(t.Selected.Render("► ") + symbol + " " + address)
+		} else {
+			b.WriteString("  " + symbol + " " + address)
+		}
+		b.WriteString("\n")
+	}
+	if len(p.matches) == 0 {
+		b.WriteString(t.Dim.Render("  No matching resources") + "\n")
+	}
+	return b.String()
+}
+
+// highlightPositions renders the runes of s at the given offsets with hl and
+// the rest with normal.
+func highlightPositions(s string, positions []int, hl, normal func(...string) string) string {
+	marked := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		marked[p] = true
+	}
+
+	var b, run strings.Builder
+	runMarked := false
+	flush := func() {
+		if run.Len() == 0 {
+			return
+		}
+		if runMarked {
+			b.WriteString(hl(run.String()))
+		} else {
+			b.WriteString(normal(run.String()))
+		}
+		run.Reset()
+	}
+	for i, r := range []rune(s) {
+		if marked[i] != runMarked {
+			flush()
+			runMarked = marked[i]
+		}
+		run.WriteRune(r)
+	}
+	flush()
+	return b.String()
+}
+
+// renderPaletteFooter renders the key hints shown while the palette is open
+func (m Model) renderPaletteFooter() string {
+	return m.theme().Dim.Render("↑/↓:select  Enter:go to  Esc:close")
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// paletteResources is a long plan of subnets followed by two other resources
+var paletteResources = append(subnetResources(40),
+	planResource("module.db.aws_db_instance.main", "update", "~ instance_class = \"db.t3.micro\" -> \"db.t3.small\""),
+	planResource("aws_iam_role.deploy", "destroy"),
+)
+
+func subnetResources(n int) []ResourceChange {
+	var resources []ResourceChange
+	for i := 0; i < n; i++ {
+		resources = append(resources, planResource(fmt.Sprintf("aws_subnet.private[%d]", i), "create",
+			"+ cidr_block = \"10.0.0.0/24\"", "+ vpc_id = (known after apply)"))
+	}
+	return resources
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	score, positions, ok := fuzzyMatch("dbinst", "module.db.aws_db_instance.main")
+	if !ok {
+		t.Fatal("expected subsequence to match")
+	}
+	if score <= 0 {
+		t.Errorf("expected positive score, got %d", score)
+	}
+	if positions[0] != 7 {
+		t.Errorf("expected first rune to prefer the segment start, got positions %v", positions)
+	}
+
+	if _, _, ok := fuzzyMatch("xyz", "aws_vpc.main"); ok {
+		t.Error("expected non-subsequence to fail")
+	}
+	if _, _, ok := fuzzyMatch("VPC", "aws_vpc.main"); !ok {
+		t.Error("expected matching to be case-insensitive")
+	}
+}
+
+func TestFuzzyMatchRanksTighterMatchesFirst(t *testing.T) {
+	tight, _, _ := fuzzyMatch("role", "aws_iam_role.deploy")
+	loose, _, _ := fuzzyMatch("role", "aws_route_table.local_exit")
+	if tight <= loose {
+		t.Errorf("expected consecutive match (%d) to outrank scattered match (%d)", tight, loose)
+	}
+}
+
+func TestPaletteFiltersAndRanks(t *testing.T) {
+	m := planModel(120, 20, paletteResources...)
+	m = typeKeys(m, "ctrl+p")
+	if m.palette == nil {
+		t.Fatal("expected Ctrl+P to open the palette")
+	}
+	if len(m.palette.matches) != len(m.resources) {
+		t.Errorf("expected empty query to list every resource, got %d", len(m.palette.matches))
+	}
+
+	m = typeKeys(m, "iamrole")
+	if len(m.palette.matches) != 1 || m.resources[m.palette.matches[0].resourceIdx].Address != "aws_iam_role.deploy" {
+		t.Errorf("unexpected matches for iamrole: %+v", m.palette.matches)
+	}
+
+	view := stripANSI(m.View())
+	if !strings.Contains(view, "- aws_iam_role.deploy") {
+		t.Errorf("expected match shown with its action symbol, got:\n%s", view)
+	}
+}
+
+func TestPaletteGoToResourceExpandsAndCenters(t *testing.T) {
+	m := planModel(120, 20, paletteResources...)
+	m = typeKeys(m, "ctrl+p", "private[25]", "enter")
+	if m.palette != nil {
+		t.Fatal("expected Enter to close the palette")
+	}
+
+	line := m.lines[m.cursor]
+	if line.Type != LineTypeResource || m.resources[line.ResourceIdx].Address != "aws_subnet.private[25]" {
+		t.Fatalf("cursor on %+v, want aws_subnet.private[25]", line)
+	}
+	if !m.resources[line.ResourceIdx].Expanded {
+		t.Error("expected the selected resource to be expanded")
+	}
+	if want := m.cursor - m.visibleHeight()/2; m.offset != want {
+		t.Errorf("offset = %d, want %d (cursor centered)", m.offset, want)
+	}
+}
+
+func TestPaletteSelectionAndCancel(t *testing.T) {
+	m := planModel(120, 20, paletteResources...)
+	m = typeKeys(m, "ctrl+p", "private", "down", "down", "up", "enter")
+	if got := m.resources[m.lines[m.cursor].ResourceIdx].Address; got != "aws_subnet.private[1]" {
+		t.Errorf("expected second match selected, got %s", got)
+	}
+
+	before := m.cursor
+	m = typeKeys(m, "ctrl+p", "deploy", "esc")
+	if m.palette != nil || m.cursor != before {
+		t.Error("expected Esc to close the palette without moving")
+	}
+}
+
+func TestPaletteClearsFiltersHidingTarget(t *testing.T) {
+	m := planModel(120, 20, paletteResources...)
+	m = typeKeys(m, "f", "c")
+	m = typeKeys(m, "ctrl+p", "deploy", "enter")
+	if len(m.actionFilter) != 0 {
+		t.Error("expected the action filter to be cleared to show the target")
+	}
+	if got := m.resources[m.lines[m.cursor].ResourceIdx].Address; got != "aws_iam_role.deploy" {
+		t.Errorf("cursor on %s", got)
+	}
+}
+
+func TestHighlightPositions(t *testing.T) {
+	wrap := func(s ...string) string { return "[" + strings.Join(s, "") + "]" }
+	plain := func(s ...string) string { return strings.Join(s, "") }
+	got := highlightPositions("aws_vpc", []int{4, 5}, wrap, plain)
+	if want := "aws_[vp]c"; !reflect.DeepEqual(got, want) {
+		t.Errorf("highlightPositions = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/creack/pty"
+)
+
+// passthroughExitKey returns from the raw terminal to the parsed view
+const passthroughExitKey = tea.KeyCtrlCloseBracket
+
+// keySequences are the bytes a terminal sends for special keys
+var keySequences = map[tea.KeyType]string{
+	tea.KeyUp:        "\x1b[A",
+	tea.KeyDown:      "\x1b[B",
+	tea.KeyRight:     "\x1b[C",
+	tea.KeyLeft:      "\x1b[D",
+	tea.KeyHome:      "\x1b[H",
+	tea.KeyEnd:       "\x1b[F",
+	tea.KeyPgUp:      "\x1b[5~",
+	tea.KeyPgDown:    "\x1b[6~",
+	tea.KeyInsert:    "\x1b[2~",
+	tea.KeyDelete:    "\x1b[3~",
+	tea.KeyShiftTab:  "\x1b[Z",
+	tea.KeyCtrlUp:    "\x1b[1;5A",
+	tea.KeyCtrlDown:  "\x1b[1;5B",
+	tea.KeyCtrlRight: "\x1b[1;5C",
+	tea.KeyCtrlLeft:  "\x1b[1;5D",
+	tea.KeySpace:     " ",
+	tea.KeyF1:        "\x1bOP",
+	tea.KeyF2:        "\x1bOQ",
+	tea.KeyF3:        "\x1bOR",
+	tea.KeyF4:        "\x1bOS",
+	tea.KeyF5:        "\x1b[15~",
+	tea.KeyF6:        "\x1b[17~",
+	tea.KeyF7:        "\x1b[18~",
+	tea.KeyF8:        "\x1b[19~",
+	tea.KeyF9:        "\x1b[20~",
+	tea.KeyF10:       "\x1b[21~",
+	tea.KeyF11:       "\x1b[23~",
+	tea.KeyF12:       "\x1b[24~",
+}
+
+// keyBytes converts a key press back into the bytes a terminal would send
+func keyBytes(msg tea.KeyMsg) []byte {
+	var s string
+	switch {
+	case msg.Type == tea.KeyRunes:
+		s = string(msg.Runes)
+	case msg.Type >= 0 && msg.Type <= 0x1f, msg.Type == tea.KeyBackspace:
+		// Control keys are their own control characters
+		s = string(rune(msg.Type))
+	default:
+		s = keySequences[msg.Type]
+	}
+	if s != "" && msg.Alt {
+		s = "\x1b" + s
+	}
+	return []byte(s)
+}
+
+// passthroughBlockedMessage is logged when passthrough would bypass the
+// destructive apply guard
+const passthroughBlockedMessage = "Terminal passthrough is unavailable while an apply that destroys or replaces resources awaits approval"
+
+// togglePassthrough hands the terminal to the wrapped command, or returns
+// to the parsed view. Passthrough is refused while answering yes would need
+// the destructive apply guard, since typed keys go to Terraform unchecked.
+func (m *Model) togglePassthrough() {
+	if m.ptyFile == nil || m.screen == nil {
+		return
+	}
+	if !m.passthrough && m.needsGuard("yes") {
+		m.logs = append(m.logs, passthroughBlockedMessage)
+		m.needsSync = true
+		return
+	}
+	m.passthrough = !m.passthrough
+	m.inputMode = false
+}
+
+// leaveGuardedPassthrough returns to the parsed view when a guarded approval
+// question arrives during passthrough
+func (m *Model) leaveGuardedPassthrough() {
+	if m.passthrough && m.needsGuard("yes") {
+		m.passthrough = false
+		m.logs = append(m.logs, passthroughBlockedMessage)
+	}
+}
+
+// handlePassthroughKey sends every key except the exit key unmodified to the PTY
+func (m Model) handlePassthroughKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == passthroughExitKey {
+		m.togglePassthrough()
+		m.needsSync = true
+		return m, nil
+	}
+	if b := keyBytes(msg); len(b) > 0 {
+		if _, err := m.ptyFile.Write(b); err != nil {
+			fmt.Fprintf(os.Stderr, "[ERROR] Failed to write to PTY: %v\n", err)
+		}
+	}
+	return m, nil
+}
+
+// renderPassthrough draws the emulated terminal below a one-line banner
+func (m Model) renderPassthrough() string {
+	t := m.theme()
+	var b strings.Builder
+	b.WriteString(t.InputMode.Render("TERMINAL"))
+	b.WriteString(" " + t.Dim.Render("Keys go to "+strings.Join(m.command, " ")+"  Ctrl+]:back to terraui"))
+	for y := 0; y < m.screen.height && y < m.height-1; y++ {
+		b.WriteString("\n")
+		b.WriteString(m.screen.renderRow(y, true))
+	}
+	return b.String()
+}
+
+// newVTScreenFor returns the emulator for a PTY, sized like a default
+// terminal until the first window size is known, or nil in pipe mode
+func newVTScreenFor(ptyFile *os.File) *vtScreen {
+	if ptyFile == nil {
+		return nil
+	}
+	return newVTScreen(80, 24)
+}
+
+// resizePTY tells the wrapped command the size of the terminal it is shown in
+func (m *Model) resizePTY() {
+	if m.ptyFile == nil || m.screen == nil {
+		return
+	}
+	size := &pty.Winsize{Cols: uint16(m.screen.width), Rows: uint16(m.screen.height)}
+	if err := pty.Setsize(m.ptyFile, size); err != nil && !m.done {
+		m.logs = append(m.logs, fmt.Sprintf("Failed to resize terminal: %v", err))
+	}
+}
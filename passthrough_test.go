@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestKeyBytes(t *testing.T) {
+	tests := []struct {
+		msg  tea.KeyMsg
+		want string
+	}{
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("yés")}, "yés"},
+		{tea.KeyMsg{Type: tea.KeyEnter}, "\r"},
+		{tea.KeyMsg{Type: tea.KeyBackspace}, "\x7f"},
+		{tea.KeyMsg{Type: tea.KeyCtrlC}, "\x03"},
+		{tea.KeyMsg{Type: tea.KeyTab}, "\t"},
+		{tea.KeyMsg{Type: tea.KeyUp}, "\x1b[A"},
+		{tea.KeyMsg{Type: tea.KeySpace}, " "},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b"), Alt: true}, "\x1bb"},
+	}
+	for _, tt := range tests {
+		if got := string(keyBytes(tt.msg)); got != tt.want {
+			t.Errorf("keyBytes(%v) = %q, want %q", tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestPassthroughMode(t *testing.T) {
+	m, f := guardModel(t)
+	m.screen = newVTScreen(40, 5)
+	m.command = []string{"terraform", "login"}
+
+	updated, _ := m.Update(StreamMsg{Output: []byte("Token for app.terraform.io:\r\n  Enter a value: ")})
+	m = updated.(Model)
+
+	m = typeKeys(m, "T")
+	if !m.passthrough {
+		t.Fatal("expected T to enter passthrough mode")
+	}
+	view := stripANSI(m.View())
+	if !strings.Contains(view, "TERMINAL") || !strings.Contains(view, "Token for app.terraform.io:") {
+		t.Errorf("expected the emulated terminal, got:\n%s", view)
+	}
+
+	// Keys that terraui would normally handle go to the PTY unchanged
+	m = typeKeys(m, "q", "j", "enter")
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	m = updated.(Model)
+	if got := ptyWritten(t, f); got != "qj\r\x03" {
+		t.Errorf("expected raw keys written to the PTY, got %q", got)
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlCloseBracket})
+	m = updated.(Model)
+	if m.passthrough {
+		t.Error("expected Ctrl+] to return to the parsed view")
+	}
+}
+
+func TestPassthroughUnavailableInPipeMode(t *testing.T) {
+	m := Model{width: 80, height: 24, ready: true}
+	m = typeKeys(m, "T")
+	if m.passthrough {
+		t.Error("expected no passthrough without a PTY")
+	}
+}
+
+func TestPassthroughRefusedForGuardedApproval(t *testing.T) {
+	m, f := guardModel(t, "destroy")
+	m.screen = newVTScreen(40, 5)
+	m = typeKeys(m, "T", "yes", "enter")
+	if m.passthrough {
+		t.Fatal("expected passthrough refused while a destructive apply awaits approval")
+	}
+	if got := ptyWritten(t, f); got != "" {
+		t.Errorf("expected nothing written around the guard, got %q", got)
+	}
+	if !strings.Contains(strings.Join(m.logs, "\n"), passthroughBlockedMessage) {
+		t.Error("expected the refusal explained in the log")
+	}
+
+	// Entered earlier, passthrough ends when the guarded question arrives
+	m.prompt = ""
+	m.passthrough = true
+	updated, _ := m.Update(StreamMsg{Prompt: strPtr("Enter a value:")})
+	if m = updated.(Model); m.passthrough {
+		t.Error("expected passthrough left when a guarded approval question arrives")
+	}
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// promptContextLines is how many log lines before a prompt are searched for
+// its variable name or question.
+const promptContextLines = 20
+
+// promptDescriptionLimit caps the description lines shown above the prompt
+const promptDescriptionLimit = 6
+
+// minRedactLength is the shortest secret redacted wherever it appears in the
+// logs; shorter ones are only redacted from Terraform's echo of the answer.
+const minRedactLength = 4
+
+// redactedValue replaces secrets in the logs
+const redactedValue = "(sensitive value)"
+
+var (
+	// variablePromptPattern matches the header of an input variable prompt
+	variablePromptPattern = regexp.MustCompile(`^var\.([\w-]+)$`)
+	// secretNamePattern matches variable names with a word that usually means
+	// a secret, so db_password and auth_token match but bypass_cache, compass
+	// and author don't. camelCase names are split into words first.
+	secretNamePattern = regexp.MustCompile(`(^|[_-])(pass(word|wd|phrase)?|secret|token|api_?key|private_?key|credential|auth)s?($|[_-])`)
+	// camelCasePattern finds the word boundaries inside a camelCase name
+	camelCasePattern = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+)
+
+// promptContext is what Terraform printed above an "Enter a value:" prompt
+type promptContext struct {
+	Variable    string   // Variable name for var.* prompts
+	Question    string   // Question line, e.g. "Do you want to perform these actions?"
+	Description []string // Indented text between the header and the prompt
+	Masked      bool     // Input is hidden while typing and redacted from the logs
+}
+
+// parsePromptContext reads the variable name or question and its description
+// from the log lines printed before a prompt.
+func parsePromptContext(logs []string) promptContext {
+	var ctx promptContext
+	var desc []string
+	for i := len(logs) - 1; i >= 0 && i >= len(logs)-promptContextLines; i-- {
+		clean := strings.TrimRight(stripANSI(logs[i]), " \t")
+		trimmed := strings.TrimSpace(clean)
+		if trimmed == "" || strings.HasPrefix(clean, " ") {
+			desc = append(desc, trimmed)
+			continue
+		}
+		if match := variablePromptPattern.FindStringSubmatch(trimmed); match != nil {
+			ctx.Variable = match[1]
+		} else if strings.HasSuffix(trimmed, "?") {
+			ctx.Question = trimmed
+		} else {
+			return promptContext{}
+		}
+		break
+	}
+	if ctx.Variable == "" && ctx.Question == "" {
+		return promptContext{}
+	}
+
+	// desc was collected bottom-up; reverse it and drop surrounding blanks
+	for i := len(desc) - 1; i >= 0; i-- {
+		if desc[i] != "" || (len(ctx.Description) > 0 && ctx.Description[len(ctx.Description)-1] != "") {
+			ctx.Description = append(ctx.Description, desc[i])
+		}
+	}
+	for len(ctx.Description) > 0 && ctx.Description[len(ctx.Description)-1] == "" {
+		ctx.Description = ctx.Description[:len(ctx.Description)-1]
+	}
+	return ctx
+}
+
+// isSecretVariable reports whether a variable's input should be masked: its
+// name matches one of the configured sensitive_variables globs or looks like
+// a secret.
+func isSecretVariable(name string, patterns []string) bool {
+	if name == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	words := strings.ToLower(camelCasePattern.ReplaceAllString(name, "${1}_${2}"))
+	return secretNamePattern.MatchString(words)
+}
+
+// setPrompt records a prompt from the stream. The context is read once per
+// prompt so a mask toggled while typing is kept.
+func (m *Model) setPrompt(prompt string) {
+	if m.prompt == "" {
+		m.promptCtx = parsePromptContext(m.logs)
+		m.promptCtx.Masked = isSecretVariable(m.promptCtx.Variable, m.config.SensitiveVariables)
+	}
+	m.prompt = prompt
+}
+
+// toggleMask shows or hides the input of the current prompt
+func (m *Model) toggleMask() {
+	m.promptCtx.Masked = !m.promptCtx.Masked
+}
+
+// rememberSecret records a masked answer so Terraform's echo of it and any
+// later occurrences are redacted from the logs.
+func (m *Model) rememberSecret(answer string) {
+	if !m.promptCtx.Masked || answer == "" {
+		return
+	}
+	m.redactEcho = true
+	m.screenEcho = answer
+	if utf8.RuneCountInString(answer) >= minRedactLength {
+		m.secrets = append(m.secrets, answer)
+	}
+}
+
+// redact removes submitted secrets from a line of output
+func (m *Model) redact(line string) string {
+	if m.redactEcho {
+		if idx := strings.Index(line, "Enter a value:"); idx >= 0 {
+			m.redactEcho = false
+			line = line[:idx] + "Enter a value: " + redactedValue
+		}
+	}
+	for _, secret := range m.secrets {
+		line = strings.ReplaceAll(line, secret, redactedValue)
+	}
+	return line
+}
+
+// redactOutput removes submitted secrets from raw output before it reaches
+// the terminal screen shown in passthrough, including the terminal's echo of
+// a masked answer of any length
+func (m *Model) redactOutput(out []byte) []byte {
+	if m.screenEcho != "" {
+		if idx := bytes.Index(out, []byte(m.screenEcho)); idx >= 0 {
+			out = append(append(append([]byte(nil), out[:idx]...), redactedValue...), out[idx+len(m.screenEcho):]...)
+			m.screenEcho = ""
+		}
+	}
+	for _, secret := range m.secrets {
+		out = bytes.ReplaceAll(out, []byte(secret), []byte(redactedValue))
+	}
+	return out
+}
+
+// redactDiagnostic removes submitted secrets from a diagnostic
+func (m *Model) redactDiagnostic(d *Diagnostic) {
+	if len(m.secrets) == 0 {
+		return
+	}
+	d.Summary = m.redact(d.Summary)
+	for i := range d.Detail {
+		d.Detail[i].Content = m.redact(d.Detail[i].Content)
+	}
+}
+
+// renderInput renders the typed input with its cursor, hidden if the prompt
+// is masked
+func (m Model) renderInput() string {
+	t := m.theme()
+	var mask rune
+	if m.promptCtx.Masked {
+		mask = '•'
+	}
+	return m.input.render(mask, t.Create, t.Dim)
+}
+
+// promptHeight returns the number of lines the pinned prompt occupies
+func (m Model) promptHeight() int {
+	if m.prompt == "" {
+		return 0
+	}
+	h := 2 // Separating blank line and the prompt itself
+	if m.promptCtx.Variable != "" || m.promptCtx.Question != "" {
+		h++
+	}
+	return h + min(len(m.promptCtx.Description), promptDescriptionLimit)
+}
+
+// renderPrompt renders the pinned prompt: the variable or question, its
+// description, and the prompt line with the input being typed.
+func (m Model) renderPrompt() string {
+	t := m.theme()
+	var b strings.Builder
+
+	switch {
+	case m.promptCtx.Variable != "":
+		b.WriteString(t.Warning.Render("var."+m.promptCtx.Variable) + "\n")
+	case m.promptCtx.Question != "":
+		b.WriteString(t.Warning.Render(m.promptCtx.Question) + "\n")
+	}
+	for i, line := range m.promptCtx.Description {
+		if i == promptDescriptionLimit {
+			break
+		}
+		b.WriteString("  " + t.Dim.Render(line) + "\n")
+	}
+
+	b.WriteString(t.Prompt.Render(">> " + m.prompt))
+	if m.inputMode {
+		b.WriteString(" " + m.renderInput())
+		if m.promptCtx.Masked {
+			b.WriteString(t.Dim.Render("  (hidden, Ctrl+s to show)"))
+		}
+	} else if m.promptCtx.Masked {
+		b.WriteString(t.Dim.Render("  (input will be hidden)"))
+	}
+	return b.String()
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParsePromptContext(t *testing.T) {
+	ctx := parsePromptContext([]string{
+		"Initializing...",
+		"var.db_password",
+		"  Password for the \x1b[1madmin\x1b[0m user.",
+		"",
+		"  Must be at least 12 characters.",
+		"",
+	})
+	if ctx.Variable != "db_password" || ctx.Question != "" {
+		t.Errorf("unexpected header: %+v", ctx)
+	}
+	want := []string{"Password for the admin user.", "", "Must be at least 12 characters."}
+	if strings.Join(ctx.Description, "|") != strings.Join(want, "|") {
+		t.Errorf("description = %q, want %q", ctx.Description, want)
+	}
+
+	ctx = parsePromptContext([]string{
+		"Plan: 1 to add, 0 to change, 0 to destroy.",
+		"",
+		"Do you want to perform these actions?",
+		"  Terraform will perform the actions described above.",
+		"  Only 'yes' will be accepted to approve.",
+		"",
+	})
+	if ctx.Question != "Do you want to perform these actions?" || len(ctx.Description) != 2 {
+		t.Errorf("unexpected approval context: %+v", ctx)
+	}
+
+	if ctx := parsePromptContext([]string{"Some unrelated output", ""}); ctx.Variable != "" || ctx.Question != "" {
+		t.Errorf("expected no context for an unrecognised prompt, got %+v", ctx)
+	}
+}
+
+func TestIsSecretVariable(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		want     bool
+	}{
+		{"db_password", nil, true},
+		{"github_token", nil, true},
+		{"stripe_api_key", nil, true},
+		{"region", nil, false},
+		{"dbPassword", nil, true},
+		{"auth_token", nil, true},
+		{"oauth_client_secrets", nil, true},
+		{"bypass_cache", nil, false},
+		{"compass_heading", nil, false},
+		{"author", nil, false},
+		{"tokenizer", nil, false},
+		{"region", []string{"reg*"}, true},
+		{"", []string{"*"}, false},
+	}
+	for _, tt := range tests {
+		if got := isSecretVariable(tt.name, tt.patterns); got != tt.want {
+			t.Errorf("isSecretVariable(%q, %q) = %v, want %v", tt.name, tt.patterns, got, tt.want)
+		}
+	}
+}
+
+func TestMaskedPromptInput(t *testing.T) {
+	m, f := guardModel(t)
+	m.prompt = ""
+	m.logs = []string{"var.db_password", "  Password for the admin user.", ""}
+	updated, _ := m.Update(StreamMsg{Prompt: strPtr("Enter a value:")})
+	m = updated.(Model)
+	m.height = 40
+
+	if !m.promptCtx.Masked {
+		t.Fatal("expected a password variable to be masked")
+	}
+	m = typeKeys(m, "i", "hunter22")
+	rendered := stripANSI(m.renderPrompt())
+	for _, want := range []string{"var.db_password", "Password for the admin user.", "••••••••"} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("expected %q in the prompt dialog, got:\n%s", want, rendered)
+		}
+	}
+	if strings.Contains(rendered, "hunter22") {
+		t.Error("expected the typed value to be hidden")
+	}
+
+	m = typeKeys(m, "enter")
+	if got := ptyWritten(t, f); got != "hunter22\n" {
+		t.Errorf("expected the real value written to the PTY, got %q", got)
+	}
+
+	// Terraform echoes the answer, and may repeat it later
+	for _, line := range []string{"  Enter a value: hunter22", "Error: password hunter22 is too weak"} {
+		updated, _ = m.Update(StreamMsg{LogLine: strPtr(line)})
+		m = updated.(Model)
+	}
+	for _, line := range m.logs {
+		if strings.Contains(line, "hunter22") {
+			t.Errorf("expected the secret redacted from the logs, got %q", line)
+		}
+	}
+}
+
+func TestMaskToggleAndShortSecretEcho(t *testing.T) {
+	m, _ := guardModel(t)
+	m.prompt = ""
+	m.logs = []string{"var.pin", "  Your PIN", ""}
+	m.setPrompt("Enter a value:")
+	if m.promptCtx.Masked {
+		t.Fatal("expected pin not to be masked by default")
+	}
+
+	m = typeKeys(m, "i", "42")
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	m = updated.(Model)
+	if !m.promptCtx.Masked || strings.Contains(stripANSI(m.renderPrompt()), "42") {
+		t.Fatal("expected Ctrl+s to hide the input")
+	}
+	m.setPrompt("Enter a value:")
+	if !m.promptCtx.Masked {
+		t.Fatal("expected a repeated prompt message to keep the toggled mask")
+	}
+
+	m = typeKeys(m, "enter")
+	for _, line := range []string{"  Enter a value: 42", "Apply complete! Resources: 42 added"} {
+		updated, _ = m.Update(StreamMsg{LogLine: strPtr(line)})
+		m = updated.(Model)
+	}
+	if got := m.logs[len(m.logs)-2]; got != "  Enter a value: "+redactedValue {
+		t.Errorf("expected the echo redacted, got %q", got)
+	}
+	if got := m.logs[len(m.logs)-1]; !strings.Contains(got, "42 added") {
+		t.Errorf("expected short secrets not to be redacted elsewhere, got %q", got)
+	}
+}
+
+func TestMaskedAnswerRedactedFromTerminalScreen(t *testing.T) {
+	m, _ := guardModel(t)
+	m.screen = newVTScreen(60, 5)
+	m.prompt = ""
+	m.logs = []string{"var.db_password", ""}
+	m.setPrompt("Enter a value:")
+	m = typeKeys(m, "i", "hunter22", "enter")
+
+	for _, out := range []string{"  Enter a value: ", "hunter22\r\n", "Error: password hunter22 is too weak\r\n"} {
+		updated, _ := m.Update(StreamMsg{Output: []byte(out)})
+		m = updated.(Model)
+	}
+	screen := strings.Join(screenText(m.screen), "\n")
+	if strings.Contains(screen, "hunter22") {
+		t.Errorf("expected the secret redacted from the terminal screen, got:\n%s", screen)
+	}
+	if !strings.Contains(screen, "Enter a value: "+redactedValue) {
+		t.Errorf("expected the echo replaced, got:\n%s", screen)
+	}
+}
+
+func strPtr(s string) *string { return &s }
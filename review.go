@@ -0,0 +1,188 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// reviewFile is the on-disk record of review marks for one plan
+type reviewFile struct {
+	Fingerprint string   `json:"fingerprint"`
+	Reviewed    []string `json:"reviewed"`
+}
+
+// defaultReviewDir returns the directory review marks are stored in: the
+// config's review_dir, or reviews/ next to the config file in use.
+func defaultReviewDir(cfg Config) string {
+	if cfg.ReviewDir != "" {
+		return cfg.ReviewDir
+	}
+	if cfg.path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(cfg.path), "reviews")
+}
+
+// planFingerprint hashes the parsed plan so marks are only restored for the
+// same set of changes.
+func planFingerprint(resources []ResourceChange) string {
+	h := sha256.New()
+	for _, rc := range resources {
+		fmt.Fprintf(h, "%s\x00%s\x00", rc.Address, rc.Action)
+		for _, attr := range rc.Attributes {
+			fmt.Fprintf(h, "%s\x00", attr)
+		}
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// reviewPath returns the file holding marks for a fingerprint
+func reviewPath(dir, fingerprint string) string {
+	return filepath.Join(dir, fingerprint+".json")
+}
+
+// loadReviewFile reads the marks for a fingerprint. A missing file yields none.
+func loadReviewFile(dir, fingerprint string) ([]string, error) {
+	data, err := os.ReadFile(reviewPath(dir, fingerprint))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var f reviewFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", reviewPath(dir, fingerprint), err)
+	}
+	return f.Reviewed, nil
+}
+
+// saveReviewFile writes the marks for a fingerprint
+func saveReviewFile(dir, fingerprint string, reviewed []string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(reviewFile{Fingerprint: fingerprint, Reviewed: reviewed}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(reviewPath(dir, fingerprint), data, 0o644)
+}
+
+// planComplete reports whether all resources have been parsed: the stream
+// has ended or Terraform is waiting at a prompt.
+func (m Model) planComplete() bool {
+	return m.done || m.prompt != ""
+}
+
+// restoreReviews loads saved marks once the plan is complete
+func (m *Model) restoreReviews() {
+	if m.reviewDir == "" || !m.planComplete() || len(m.resources) == 0 {
+		return
+	}
+	fp := planFingerprint(m.resources)
+	if fp == m.reviewFingerprint {
+		return
+	}
+	m.reviewFingerprint = fp
+
+	saved, err := loadReviewFile(m.reviewDir, fp)
+	if err != nil {
+		m.logs = append(m.logs, fmt.Sprintf("Failed to load review marks: %v", err))
+		return
+	}
+	for _, addr := range saved {
+		if m.reviewed == nil {
+			m.reviewed = make(map[string]bool)
+		}
+		m.reviewed[addr] = true
+	}
+}
+
+// saveReviews persists the marks for the complete plan
+func (m *Model) saveReviews() {
+	if m.reviewDir == "" || !m.planComplete() {
+		return
+	}
+	var addrs []string
+	for addr := range m.reviewed {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	if err := saveReviewFile(m.reviewDir, planFingerprint(m.resources), addrs); err != nil {
+		m.logs = append(m.logs, fmt.Sprintf("Failed to save review marks: %v", err))
+	}
+}
+
+// toggleReviewed marks or unmarks the resource under the cursor
+func (m *Model) toggleReviewed() {
+	resIdx := m.selectedResource()
+	if m.tab != TabPlan || resIdx < 0 || resIdx >= len(m.resources) {
+		return
+	}
+	addr := m.resources[resIdx].Address
+	if m.reviewed == nil {
+		m.reviewed = make(map[string]bool)
+	}
+	if m.reviewed[addr] {
+		delete(m.reviewed, addr)
+	} else {
+		m.reviewed[addr] = true
+	}
+	m.saveReviews()
+}
+
+// reviewedCount returns the number of resources marked as reviewed
+func (m Model) reviewedCount() int {
+	n := 0
+	for _, rc := range m.resources {
+		if m.reviewed[rc.Address] {
+			n++
+		}
+	}
+	return n
+}
+
+// jumpToUnreviewed moves the cursor to the next visible unreviewed resource
+// after the cursor, wrapping around.
+func (m *Model) jumpToUnreviewed() {
+	if m.tab != TabPlan || len(m.lines) == 0 {
+		return
+	}
+	n := len(m.lines)
+	for k := 1; k <= n; k++ {
+		idx := (m.cursor + k) % n
+		line := m.lines[idx]
+		if line.Type == LineTypeResource && !m.reviewed[m.resources[line.ResourceIdx].Address] {
+			m.cursor = idx
+			m.ensureCursorVisible()
+			return
+		}
+	}
+}
+
+// reviewMark returns the mark shown after a resource's expand icon, or "" if
+// nothing has been reviewed yet.
+func (m Model) reviewMark(address string) string {
+	if len(m.reviewed) == 0 {
+		return ""
+	}
+	if m.reviewed[address] {
+		return " ✓"
+	}
+	return "  "
+}
+
+// reviewProgress returns the header label, e.g. "41/57 reviewed"
+func (m Model) reviewProgress() string {
+	if len(m.reviewed) == 0 || len(m.resources) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d reviewed", m.reviewedCount(), len(m.resources))
+}
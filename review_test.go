@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// reviewResources is a small complete plan to mark
+var reviewResources = []ResourceChange{
+	planResource("aws_vpc.main", "create", "+ id = (known after apply)"),
+	planResource("aws_subnet.a", "create", "+ id = (known after apply)"),
+	planResource("aws_subnet.b", "create", "+ id = (known after apply)"),
+}
+
+func TestReviewMarksAndProgress(t *testing.T) {
+	m := planModel(120, 20, reviewResources...)
+	m.done = true
+	if m.reviewProgress() != "" || strings.Contains(stripANSI(m.renderLine(0)), "✓") {
+		t.Fatal("expected no review marks before any resource is reviewed")
+	}
+
+	m = typeKeys(m, "x")
+	if !m.reviewed["aws_vpc.main"] {
+		t.Fatal("expected x to mark the resource under the cursor")
+	}
+	if line := stripANSI(m.renderLine(1)); !strings.Contains(line, "▸   +") {
+		t.Errorf("expected unreviewed resources to keep alignment, got %q", line)
+	}
+	m.cursor = 1
+	if line := stripANSI(m.renderLine(0)); !strings.Contains(line, "▸ ✓ + aws_vpc.main") {
+		t.Errorf("expected a checkmark on the reviewed resource, got %q", line)
+	}
+	if header := stripANSI(m.renderHeader()); !strings.Contains(header, "1/3 reviewed") {
+		t.Errorf("expected review progress in the header, got %q", header)
+	}
+
+	m.cursor = 0
+	m = typeKeys(m, "x")
+	if m.reviewed["aws_vpc.main"] {
+		t.Error("expected x to unmark a reviewed resource")
+	}
+}
+
+func TestJumpToUnreviewed(t *testing.T) {
+	m := planModel(120, 20, reviewResources...)
+	m.done = true
+	m = typeKeys(m, "x", "j", "x", "g", "u")
+	if m.cursor != 2 {
+		t.Errorf("expected u to jump to the next unreviewed resource, got cursor %d", m.cursor)
+	}
+	m = typeKeys(m, "x", "u")
+	if m.cursor != 2 {
+		t.Errorf("expected u to stay put when everything is reviewed, got cursor %d", m.cursor)
+	}
+}
+
+func TestReviewMarksPersistByPlanFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	m := planModel(120, 20, reviewResources...)
+	m.done, m.reviewDir = true, dir
+	m.restoreReviews()
+	m = typeKeys(m, "x", "j", "x")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one review file, got %v (%v)", entries, err)
+	}
+
+	// Reopening the same plan restores the marks
+	reopened := planModel(120, 20, reviewResources...)
+	reopened.done, reopened.reviewDir = true, dir
+	reopened.restoreReviews()
+	if reopened.reviewedCount() != 2 || !reopened.reviewed["aws_subnet.a"] {
+		t.Errorf("expected marks restored, got %v", reopened.reviewed)
+	}
+
+	// A different plan does not pick them up
+	changed := planModel(120, 20, reviewResources...)
+	changed.done, changed.reviewDir = true, dir
+	changed.resources[0].Attributes = []string{"+ cidr_block = \"10.1.0.0/16\""}
+	changed.restoreReviews()
+	if changed.reviewedCount() != 0 {
+		t.Errorf("expected no marks for a different plan, got %v", changed.reviewed)
+	}
+}
+
+func TestReviewMarksWaitForCompletePlan(t *testing.T) {
+	dir := t.TempDir()
+	m := planModel(120, 20, reviewResources...)
+	m.reviewDir = dir
+	m = typeKeys(m, "x")
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Error("expected marks not to be saved while the plan is still streaming")
+	}
+
+	m.prompt = "Enter a value:"
+	m.restoreReviews()
+	m = typeKeys(m, "j", "x")
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Error("expected marks saved once Terraform is waiting at the prompt")
+	}
+}
+
+func TestPlanFingerprint(t *testing.T) {
+	a := []ResourceChange{{Address: "aws_vpc.main", Action: "create", Attributes: []string{"+ a = 1"}}}
+	b := []ResourceChange{{Address: "aws_vpc.main", Action: "update", Attributes: []string{"+ a = 1"}}}
+	if planFingerprint(a) == planFingerprint(b) {
+		t.Error("expected different actions to change the fingerprint")
+	}
+	if planFingerprint(a) != planFingerprint(append([]ResourceChange{}, a...)) {
+		t.Error("expected the fingerprint to be stable")
+	}
+}
+
+func TestDefaultReviewDirFollowsConfigPath(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := loadConfig(filepath.Join(dir, "custom.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := defaultReviewDir(cfg), filepath.Join(dir, "reviews"); got != want {
+		t.Errorf("expected marks beside the config in use, got %q, want %q", got, want)
+	}
+	cfg.ReviewDir = "/shared/reviews"
+	if got := defaultReviewDir(cfg); got != "/shared/reviews" {
+		t.Errorf("expected review_dir to win, got %q", got)
+	}
+	if got := defaultReviewDir(Config{}); got != "" {
+		t.Errorf("expected saving disabled without a config path, got %q", got)
+	}
+}
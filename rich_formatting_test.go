@@ -13,7 +13,7 @@ func TestRichFormattingRendering(t *testing.T) {
 	lipgloss.SetColorProfile(termenv.ANSI)
 
 	m := &Model{renderingMode: RenderingModeDashboard,
-		showLogs: true, // Diagnostics are now shown in LOG view only
+		tab: TabLog, // Diagnostics are now shown in LOG view only
 		diagnostics: []Diagnostic{
 			{
 				Severity: "error",
@@ -152,7 +152,7 @@ func TestExitCodeDeterminesErrorState(t *testing.T) {
 // TestAutoSwitchToLogViewOnError verifies view switches on error
 func TestAutoSwitchToLogViewOnError(t *testing.T) {
 	m := Model{
-		showLogs: false,
+		tab:      TabPlan,
 		exitCode: 0,
 		hasError: false,
 	}
@@ -163,7 +163,7 @@ func TestAutoSwitchToLogViewOnError(t *testing.T) {
 	updatedM, _ := m.Update(msg)
 	finalM := updatedM.(Model)
 
-	if !finalM.showLogs {
+	if finalM.tab != TabLog {
 		t.Error("Expected view to auto-switch to LOG on error")
 	}
 	if !finalM.hasError {
@@ -174,8 +174,8 @@ func TestAutoSwitchToLogViewOnError(t *testing.T) {
 // TestLogViewOrder verifies logs appear before diagnostics in LOG view
 func TestLogViewOrder(t *testing.T) {
 	m := &Model{
-		showLogs: true,
-		width:    80,
+		tab:   TabLog,
+		width: 80,
 		logs: []string{
 			"Initializing the backend...",
 			"Refreshing state...",
@@ -223,8 +223,8 @@ func TestLogViewOrder(t *testing.T) {
 // TestPlanViewHasNoDiagnostics verifies PLAN view excludes diagnostics
 func TestPlanViewHasNoDiagnostics(t *testing.T) {
 	m := &Model{
-		showLogs: false,
-		width:    80,
+		tab:   TabPlan,
+		width: 80,
 		resources: []ResourceChange{
 			{Address: "test_resource", Action: "create"},
 		},
@@ -290,4 +290,4 @@ func TestCancellationWorksWithExternalContext(t *testing.T) {
 	case <-time.After(1 * time.Second):
 		t.Fatal("FAILED: Goroutine did not exit (streamChan not closed) after calling cancelFunc")
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Escape sequences used to highlight search matches. Reverse video is used so
+// the match stays visible whatever colors the line was rendered with.
+const (
+	highlightOn  = "\x1b[7m"
+	highlightOff = "\x1b[27m"
+)
+
+// searchState is the active search pattern
+type searchState struct {
+	query    string
+	backward bool // Started with ? rather than /
+	re       *regexp.Regexp
+}
+
+// compileSearch compiles a query as a regular expression, falling back to a
+// literal match if it is not valid regex. Smart-case: the search is case
+// insensitive unless the query contains an uppercase letter.
+func compileSearch(query string) *regexp.Regexp {
+	if query == "" {
+		return nil
+	}
+	pattern := query
+	if _, err := regexp.Compile(pattern); err != nil {
+		pattern = regexp.QuoteMeta(query)
+	}
+	hasUpper := false
+	for _, r := range query {
+		if unicode.IsUpper(r) {
+			hasUpper = true
+			break
+		}
+	}
+	if !hasUpper {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil
+	}
+	return re
+}
+
+// lineText returns the plain text of a display line for matching
+func (m Model) lineText(idx int) string {
+	line := m.lines[idx]
+	if line.Type == LineTypeResource && line.ResourceIdx >= 0 && line.ResourceIdx < len(m.resources) {
+		rc := m.resources[line.ResourceIdx]
+		return rc.Address + " " + rc.ActionText
+	}
+	if line.Type == LineTypeDiff {
+		return line.Content + " " + line.Aside
+	}
+	return stripANSI(line.Content)
+}
+
+// collapsedMatch reports whether the line is a collapsed resource header whose
+// hidden attributes contain a match.
+func (m Model) collapsedMatch(idx int, re *regexp.Regexp) bool {
+	line := m.lines[idx]
+	if line.Type != LineTypeResource || line.ResourceIdx < 0 || line.ResourceIdx >= len(m.resources) {
+		return false
+	}
+	rc := m.resources[line.ResourceIdx]
+	if rc.Expanded {
+		return false
+	}
+	for _, attr := range rc.Attributes {
+		if re.MatchString(attr) {
+			return true
+		}
+	}
+	return false
+}
+
+// findMatch returns the index of the next line matching re after (or before)
+// from, wrapping around. Collapsed resources whose attributes match are
+// expanded and the cursor lands on the matching attribute.
+func (m *Model) findMatch(from int, backward bool, re *regexp.Regexp) (int, bool) {
+	n := len(m.lines)
+	for k := 1; k <= n; k++ {
+		idx := (from + k) % n
+		if backward {
+			idx = ((from-k)%n + n) % n
+		}
+
+		if backward && m.collapsedMatch(idx, re) {
+			return m.expandToMatch(idx, true, re), true
+		}
+		if re.MatchString(m.lineText(idx)) {
+			return idx, true
+		}
+		if !backward && m.collapsedMatch(idx, re) {
+			return m.expandToMatch(idx, false, re), true
+		}
+	}
+	return 0, false
+}
+
+// expandToMatch expands the resource at header line idx and returns the line
+// of its first (or last) matching attribute. While a query is being typed the
+// expansion is recorded so it can be undone.
+func (m *Model) expandToMatch(idx int, last bool, re *regexp.Regexp) int {
+	resIdx := m.lines[idx].ResourceIdx
+	m.resources[resIdx].Expanded = true
+	if m.searching {
+		m.searchExpanded = append(m.searchExpanded, resIdx)
+	}
+	m.rebuildLines()
+
+	found := idx
+	for i := idx + 1; i < len(m.lines) && m.lines[i].ResourceIdx == resIdx && m.lines[i].Type != LineTypeResource; i++ {
+		if re.MatchString(m.lineText(i)) {
+			found = i
+			if !last {
+				break
+			}
+		}
+	}
+	return found
+}
+
+// jumpToMatch moves the cursor to the next match in the given direction
+func (m *Model) jumpToMatch(backward bool) {
+	if m.search == nil || m.search.re == nil || len(m.lines) == 0 {
+		return
+	}
+	if idx, ok := m.findMatch(m.cursor, backward, m.search.re); ok {
+		m.cursor = idx
+		m.ensureCursorVisible()
+	}
+}
+
+// undoSearchExpansion collapses the resources expanded for the query being
+// typed, so line indices are as they were when the search started
+func (m *Model) undoSearchExpansion() {
+	if len(m.searchExpanded) == 0 {
+		return
+	}
+	for _, resIdx := range m.searchExpanded {
+		m.resources[resIdx].Expanded = false
+	}
+	m.searchExpanded = nil
+	m.rebuildLines()
+}
+
+// startSearch opens the search prompt
+func (m *Model) startSearch(backward bool) {
+	m.searching = true
+	m.searchInput = ""
+	m.searchBackward = backward
+	m.searchOrigin = m.cursor
+	m.searchExpanded = nil
+}
+
+// handleSearchInput processes keyboard input while typing a search query.
+// The cursor follows the first match as the query is typed.
+func (m Model) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.undoSearchExpansion()
+		m.searching = false
+		m.cursor = m.searchOrigin
+		m.ensureCursorVisible()
+		return m, nil
+
+	case tea.KeyEnter:
+		m.searching = false
+		m.searchExpanded = nil
+		if m.searchInput == "" {
+			// An empty query repeats the previous search, as in vim
+			if m.search != nil {
+				m.search.backward = m.searchBackward
+				m.jumpToMatch(m.searchBackward)
+			}
+			return m, nil
+		}
+		m.search = &searchState{query: m.searchInput, backward: m.searchBackward, re: compileSearch(m.searchInput)}
+		return m, nil
+
+	case tea.KeyBackspace, tea.KeyDelete:
+		if r := []rune(m.searchInput); len(r) > 0 {
+			m.searchInput = string(r[:len(r)-1])
+		}
+
+	case tea.KeyRunes:
+		m.searchInput += string(msg.Runes)
+
+	case tea.KeySpace:
+		m.searchInput += " "
+
+	default:
+		return m, nil
+	}
+
+	// Incremental search from where the search started, without the
+	// expansions made for the previous query
+	m.undoSearchExpansion()
+	m.cursor = m.searchOrigin
+	if re := compileSearch(m.searchInput); re != nil && len(m.lines) > 0 {
+		if idx, ok := m.findMatch(m.searchOrigin, m.searchBackward, re); ok {
+			m.cursor = idx
+		}
+	}
+	m.clampCursor()
+	m.ensureCursorVisible()
+	return m, nil
+}
+
+// activeSearchPattern returns the pattern used for highlighting, preferring
+// the query being typed.
+func (m Model) activeSearchPattern() *regexp.Regexp {
+	if m.searching {
+		return compileSearch(m.searchInput)
+	}
+	if m.search != nil {
+		return m.search.re
+	}
+	return nil
+}
+
+// highlightMatches wraps matches of re in the visible text of a rendered line
+// with reverse video. Matching is done on the text with ANSI codes stripped,
+// and the highlight is re-applied after any escape sequence inside a match so
+// styled segments cannot cancel it.
+func highlightMatches(rendered string, re *regexp.Regexp) string {
+	plain := stripANSI(rendered)
+	matches := re.FindAllStringIndex(plain, -1)
+	if len(matches) == 0 {
+		return rendered
+	}
+
+	// Map each byte offset of the plain text to its offset in the rendered string
+	offsets := make([]int, 0, len(plain)+1)
+	escapes := ansiPattern.FindAllStringIndex(rendered, -1)
+	ei := 0
+	for i := 0; i < len(rendered); {
+		if ei < len(escapes) && escapes[ei][0] == i {
+			i = escapes[ei][1]
+			ei++
+			continue
+		}
+		offsets = append(offsets, i)
+		i++
+	}
+	offsets = append(offsets, len(rendered))
+
+	var b strings.Builder
+	pos := 0
+	for _, match := range matches {
+		if match[0] == match[1] {
+			continue
+		}
+		start, end := offsets[match[0]], offsets[match[1]-1]+1
+		b.WriteString(rendered[pos:start])
+		b.WriteString(highlightOn)
+		segment := rendered[start:end]
+		b.WriteString(ansiPattern.ReplaceAllStringFunc(segment, func(esc string) string {
+			return esc + highlightOn
+		}))
+		b.WriteString(highlightOff)
+		pos = end
+	}
+	b.WriteString(rendered[pos:])
+	return b.String()
+}
+
+// renderSearchBar renders the search prompt or the active search status
+func (m Model) renderSearchBar() string {
+	t := m.theme()
+	prefix := "/"
+	if m.searching && m.searchBackward || !m.searching && m.search != nil && m.search.backward {
+		prefix = "?"
+	}
+	if m.searching {
+		return t.Prompt.Render(prefix) + t.Default.Render(m.searchInput) + t.Dim.Render("█")
+	}
+
+	re := m.search.re
+	count := 0
+	if re != nil {
+		for i := range m.lines {
+			if re.MatchString(m.lineText(i)) {
+				count++
+			}
+		}
+	}
+	if count == 0 {
+		return t.Error.Render(fmt.Sprintf("%s%s: pattern not found", prefix, m.search.query))
+	}
+	return t.Dim.Render(fmt.Sprintf("%s%s  %d matching lines  n/N:next/prev  Esc:clear", prefix, m.search.query, count))
+}
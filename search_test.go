@@ -0,0 +1,153 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// namedKeys maps key names accepted by typeKeys to their key types; any
+// other string is typed as runes.
+var namedKeys = map[string]tea.KeyType{
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEsc,
+	"backspace": tea.KeyBackspace,
+	"tab":       tea.KeyTab,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"ctrl+u":    tea.KeyCtrlU,
+	"ctrl+p":    tea.KeyCtrlP,
+}
+
+func typeKeys(m Model, keys ...string) Model {
+	for _, k := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		if kt, ok := namedKeys[k]; ok {
+			msg = tea.KeyMsg{Type: kt}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	return m
+}
+
+// searchResources is the plan the search tests run against
+var searchResources = []ResourceChange{
+	planResource("aws_vpc.main", "create", "      + cidr_block = \"10.0.0.0/16\""),
+	planResource("aws_subnet.a", "create", "      + cidr_block = \"10.0.1.0/24\"", "      + vpc_id     = (known after apply)"),
+	planResource("aws_instance.web", "update", "      ~ instance_type = \"t3.micro\" -> \"t3.large\""),
+}
+
+func TestCompileSearch_SmartCaseAndRegex(t *testing.T) {
+	if re := compileSearch("vpc"); !re.MatchString("aws_VPC") {
+		t.Error("lowercase query should be case insensitive")
+	}
+	if re := compileSearch("VPC"); re.MatchString("aws_vpc") {
+		t.Error("query with uppercase should be case sensitive")
+	}
+	if re := compileSearch(`t3\.(micro|large)`); !re.MatchString("t3.large") {
+		t.Error("expected regex alternation to match")
+	}
+	if re := compileSearch("foo("); !re.MatchString("call foo(bar)") {
+		t.Error("invalid regex should fall back to a literal match")
+	}
+}
+
+func TestSearchExpandsCollapsedResource(t *testing.T) {
+	m := planModel(120, 0, searchResources...)
+	m = typeKeys(m, "/", "known after", "enter")
+
+	if !m.resources[1].Expanded {
+		t.Fatal("expected the resource containing the match to be expanded")
+	}
+	if got := m.lines[m.cursor]; got.Type != LineTypeAttribute || !strings.Contains(got.Content, "known after apply") {
+		t.Errorf("expected cursor on matching attribute, got %+v", got)
+	}
+	if m.resources[0].Expanded || m.resources[2].Expanded {
+		t.Error("resources without matches should stay collapsed")
+	}
+}
+
+func TestSearchNextAndPrevious(t *testing.T) {
+	m := planModel(120, 0, searchResources...)
+	m = typeKeys(m, "/", "aws_", "enter")
+	if m.cursor != 1 {
+		t.Fatalf("expected first match after the cursor at line 1, got %d", m.cursor)
+	}
+
+	m = typeKeys(m, "n")
+	if m.cursor != 2 {
+		t.Errorf("expected n to move to line 2, got %d", m.cursor)
+	}
+	m = typeKeys(m, "n")
+	if m.cursor != 0 {
+		t.Errorf("expected n to wrap to line 0, got %d", m.cursor)
+	}
+	m = typeKeys(m, "N")
+	if m.cursor != 2 {
+		t.Errorf("expected N to wrap back to line 2, got %d", m.cursor)
+	}
+
+	// Backward from aws_instance.web reaches the collapsed subnet's vpc_id first
+	m = typeKeys(m, "?", "vpc", "enter")
+	if got := m.lines[m.cursor]; got.ResourceIdx != 1 || !strings.Contains(got.Content, "vpc_id") {
+		t.Errorf("expected backward search to land on vpc_id, got %+v", got)
+	}
+	m = typeKeys(m, "n")
+	if m.cursor != 0 {
+		t.Errorf("expected n to continue backward to line 0, got line %d", m.cursor)
+	}
+}
+
+func TestSearchEscapeRestoresCursor(t *testing.T) {
+	m := planModel(120, 0, searchResources...)
+	m.cursor = 2
+	m = typeKeys(m, "/", "vpc")
+	if m.cursor == 2 {
+		t.Fatal("expected incremental search to move the cursor")
+	}
+	m = typeKeys(m, "esc")
+	if m.cursor != 2 || m.searching {
+		t.Errorf("expected Esc to cancel and restore the cursor, got cursor %d", m.cursor)
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	rendered := "\x1b[1mhello\x1b[0m world"
+	out := highlightMatches(rendered, compileSearch("lo wo"))
+
+	if stripANSI(out) != "hello world" {
+		t.Errorf("highlighting must not change visible text, got %q", stripANSI(out))
+	}
+	want := "\x1b[1mhel" + highlightOn + "lo\x1b[0m" + highlightOn + " wo" + highlightOff + "rld"
+	if out != want {
+		t.Errorf("expected %q, got %q", want, out)
+	}
+	if out := highlightMatches(rendered, compileSearch("xyz")); out != rendered {
+		t.Error("lines without matches should be unchanged")
+	}
+}
+
+func TestSearchEscapeUndoesExpansion(t *testing.T) {
+	m := planModel(120, 0, searchResources...)
+	m.cursor = 2
+	m = typeKeys(m, "/", "cidr")
+	if !m.resources[0].Expanded {
+		t.Fatal("expected the prefix to expand the first resource with a match")
+	}
+	m = typeKeys(m, `_block = "10.0.1`)
+	if m.resources[0].Expanded || !m.resources[1].Expanded {
+		t.Error("expected only the resource matching the full query to stay expanded")
+	}
+
+	m = typeKeys(m, "esc")
+	if m.resources[1].Expanded {
+		t.Error("expected Esc to collapse resources the search expanded")
+	}
+	if got := m.lines[m.cursor]; got.ResourceIdx != 2 {
+		t.Errorf("expected the cursor back on aws_instance.web, got %+v", got)
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// snippetContextLines is the number of source lines shown around a referenced range
+const snippetContextLines = 3
+
+var (
+	// locationPattern extracts the file and line number from a diagnostic marker
+	// such as "on modules/vpc/main.tf line 42, in resource ...".
+	locationPattern = regexp.MustCompile(`^\s*on\s+(.+?)\s+line\s+(\d+)`)
+	// excerptPattern matches the numbered source lines Terraform prints below a
+	// marker, e.g. "  42:   cidr_block = var.cidr".
+	excerptPattern = regexp.MustCompile(`^\s*(\d+):\s?(.*)$`)
+)
+
+// SourceLocation identifies a position in a configuration file
+type SourceLocation struct {
+	File string
+	Line int
+}
+
+// SnippetLine is a single numbered line of a source snippet
+type SnippetLine struct {
+	Number    int
+	Text      string
+	Highlight bool // Part of the range referenced by the diagnostic
+}
+
+// SourceSnippet holds the lines read from disk around a diagnostic location
+type SourceSnippet struct {
+	Location SourceLocation
+	Lines    []SnippetLine
+	Err      string // Non-empty when the file could not be read
+	Stale    bool   // File content no longer matches the excerpt Terraform printed
+}
+
+// parseLocation extracts a SourceLocation from a diagnostic marker line
+func parseLocation(content string) (SourceLocation, bool) {
+	match := locationPattern.FindStringSubmatch(stripANSI(content))
+	if match == nil {
+		return SourceLocation{}, false
+	}
+	line, err := strconv.Atoi(match[2])
+	if err != nil || line <= 0 {
+		return SourceLocation{}, false
+	}
+	return SourceLocation{File: match[1], Line: line}, true
+}
+
+// diagnosticLocation returns the location of the first marker in a diagnostic,
+// along with the source excerpt Terraform printed below it (keyed by line number).
+func diagnosticLocation(diag Diagnostic) (SourceLocation, map[int]string, bool) {
+	for i, detail := range diag.Detail {
+		if !detail.IsMarker {
+			continue
+		}
+		loc, ok := parseLocation(detail.Content)
+		if !ok {
+			continue
+		}
+		excerpt := make(map[int]string)
+		for _, next := range diag.Detail[i+1:] {
+			if next.IsMarker {
+				break
+			}
+			if match := excerptPattern.FindStringSubmatch(stripANSI(next.Content)); match != nil {
+				if n, err := strconv.Atoi(match[1]); err == nil {
+					excerpt[n] = match[2]
+				}
+			}
+		}
+		return loc, excerpt, true
+	}
+	return SourceLocation{}, nil, false
+}
+
+// loadSnippet reads the source file referenced by loc, relative to Terraform's
+// working directory dir, and returns the lines surrounding it. The highlighted range spans the marker line and any numbered
+// excerpt lines that follow it. Missing files and files that have changed since
+// the run are reported on the snippet rather than treated as fatal.
+func loadSnippet(dir string, loc SourceLocation, excerpt map[int]string) SourceSnippet {
+	snippet := SourceSnippet{Location: loc}
+
+	data, err := os.ReadFile(resolvePath(dir, loc.File))
+	if err != nil {
+		if os.IsNotExist(err) {
+			snippet.Err = fmt.Sprintf("%s not found in the working directory", loc.File)
+		} else {
+			snippet.Err = err.Error()
+		}
+		return snippet
+	}
+
+	fileLines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if loc.Line > len(fileLines) {
+		snippet.Err = fmt.Sprintf("%s has %d lines, line %d no longer exists", loc.File, len(fileLines), loc.Line)
+		snippet.Stale = true
+		return snippet
+	}
+
+	first, last := loc.Line, loc.Line
+	for n := range excerpt {
+		if n < first {
+			first = n
+		}
+		if n > last {
+			last = n
+		}
+	}
+	if last > len(fileLines) {
+		last = len(fileLines)
+	}
+
+	// Terraform trims excerpt lines, so compare ignoring surrounding whitespace
+	for n, text := range excerpt {
+		if n > len(fileLines) || strings.TrimSpace(fileLines[n-1]) != strings.TrimSpace(text) {
+			snippet.Stale = true
+			break
+		}
+	}
+
+	start := first - snippetContextLines
+	if start < 1 {
+		start = 1
+	}
+	end := last + snippetContextLines
+	if end > len(fileLines) {
+		end = len(fileLines)
+	}
+
+	for n := start; n <= end; n++ {
+		snippet.Lines = append(snippet.Lines, SnippetLine{
+			Number:    n,
+			Text:      strings.ReplaceAll(fileLines[n-1], "\t", "  "),
+			Highlight: n >= first && n <= last,
+		})
+	}
+	return snippet
+}
+
+// formatSnippetLine renders a snippet line with a right-aligned line number gutter
+func formatSnippetLine(line SnippetLine, gutter int) string {
+	marker := " "
+	if line.Highlight {
+		marker = "▶"
+	}
+	return fmt.Sprintf("%s %*d │ %s", marker, gutter, line.Number, line.Text)
+}
+
+// snippetGutterWidth returns the width needed for the largest line number
+func snippetGutterWidth(snippet SourceSnippet) int {
+	width := 1
+	for _, l := range snippet.Lines {
+		if w := len(strconv.Itoa(l.Number)); w > width {
+			width = w
+		}
+	}
+	return width
+}
+
+// toggleSource shows or hides the source snippet of the diagnostic at lineIdx.
+// The file is read when the snippet is first shown and cached afterwards.
+func (m *Model) toggleSource(lineIdx int) {
+	if lineIdx < 0 || lineIdx >= len(m.lines) {
+		return
+	}
+	diagIdx := m.lines[lineIdx].DiagIdx
+	switch m.lines[lineIdx].Type {
+	case LineTypeDiagnostic, LineTypeDiagnosticDetail, LineTypeSource:
+	default:
+		return
+	}
+	if diagIdx < 0 || diagIdx >= len(m.diagnostics) {
+		return
+	}
+
+	diag := &m.diagnostics[diagIdx]
+	diag.ShowSource = !diag.ShowSource
+	if diag.ShowSource {
+		if m.snippets == nil {
+			m.snippets = make(map[int]*SourceSnippet)
+		}
+		if _, ok := m.snippets[diagIdx]; !ok {
+			var snippet SourceSnippet
+			if loc, excerpt, ok := diagnosticLocation(*diag); ok {
+				snippet = loadSnippet(commandDir(m.command), loc, excerpt)
+			} else {
+				snippet.Err = "diagnostic has no source location"
+			}
+			m.snippets[diagIdx] = &snippet
+		}
+	}
+	m.rebuildLines()
+	m.clampCursor()
+	m.clampOffset()
+}
+
+// appendSourceLines emits the display lines for a diagnostic's source snippet
+func (m *Model) appendSourceLines(diagIdx int) {
+	snippet, ok := m.snippets[diagIdx]
+	if !ok {
+		return
+	}
+
+	heading := "source unavailable"
+	if snippet.Location.File != "" {
+		heading = fmt.Sprintf("%s:%d", snippet.Location.File, snippet.Location.Line)
+	}
+	switch {
+	case snippet.Err != "":
+		heading += " (" + snippet.Err + ")"
+	case snippet.Stale:
+		heading += " (file has changed since the run)"
+	}
+	for _, w := range wrapText(heading, m.width-4, 0) {
+		m.lines = append(m.lines, Line{
+			Type:        LineTypeSource,
+			DiagIdx:     diagIdx,
+			ResourceIdx: -1,
+			AttrIdx:     -1,
+			Content:     w,
+		})
+	}
+
+	gutter := snippetGutterWidth(*snippet)
+	for j, sl := range snippet.Lines {
+		// Continuation lines hang under the source text, past "▶ 42 │ "
+		for _, w := range wrapText(formatSnippetLine(sl, gutter), m.width-4, gutter+5) {
+			m.lines = append(m.lines, Line{
+				Type:        LineTypeSource,
+				DiagIdx:     diagIdx,
+				ResourceIdx: -1,
+				AttrIdx:     j,
+				Content:     w,
+			})
+		}
+	}
+}
+
+// renderSourceLine renders a source snippet line, highlighting the referenced range
+func (m Model) renderSourceLine(line Line, isSelected bool) string {
+	t := m.theme()
+	style := t.Dim
+
+	if snippet, ok := m.snippets[line.DiagIdx]; ok {
+		switch {
+		case line.AttrIdx < 0 && (snippet.Err != "" || snippet.Stale):
+			style = t.Warning
+		case line.AttrIdx >= 0 && line.AttrIdx < len(snippet.Lines) && snippet.Lines[line.AttrIdx].Highlight:
+			style = t.ChangeAttr.Copy().Bold(true)
+		case line.AttrIdx >= 0:
+			style = t.Default
+		}
+	}
+
+	if isSelected {
+		return t.Selected.Render("►   " + line.Content)
+	}
+	return "    " + style.Render(line.Content)
+}
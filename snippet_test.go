@@ -0,0 +1,164 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSourceFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "main.tf")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write source file: %v", err)
+	}
+	return path
+}
+
+func TestParseLocation(t *testing.T) {
+	testCases := []struct {
+		input string
+		file  string
+		line  int
+		ok    bool
+	}{
+		{"  on modules/vpc/main.tf line 42, in resource \"aws_vpc\" \"main\":", "modules/vpc/main.tf", 42, true},
+		{"on main.tf line 7:", "main.tf", 7, true},
+		{"\x1b[1mon main.tf line 3:\x1b[0m", "main.tf", 3, true},
+		{"with aws_instance.web,", "", 0, false},
+	}
+
+	for _, tc := range testCases {
+		loc, ok := parseLocation(tc.input)
+		if ok != tc.ok {
+			t.Errorf("parseLocation(%q) ok = %v, want %v", tc.input, ok, tc.ok)
+			continue
+		}
+		if ok && (loc.File != tc.file || loc.Line != tc.line) {
+			t.Errorf("parseLocation(%q) = %+v, want %s:%d", tc.input, loc, tc.file, tc.line)
+		}
+	}
+}
+
+func TestLoadSnippet_HighlightsReferencedRange(t *testing.T) {
+	var src strings.Builder
+	for i := 1; i <= 20; i++ {
+		src.WriteString("line" + strings.Repeat("x", i) + "\n")
+	}
+	path := writeSourceFile(t, src.String())
+
+	snippet := loadSnippet(".", SourceLocation{File: path, Line: 10}, map[int]string{
+		10: "line" + strings.Repeat("x", 10),
+		11: "line" + strings.Repeat("x", 11),
+	})
+
+	if snippet.Err != "" || snippet.Stale {
+		t.Fatalf("unexpected snippet state: err=%q stale=%v", snippet.Err, snippet.Stale)
+	}
+	if first, last := snippet.Lines[0].Number, snippet.Lines[len(snippet.Lines)-1].Number; first != 7 || last != 14 {
+		t.Errorf("expected lines 7-14, got %d-%d", first, last)
+	}
+	for _, l := range snippet.Lines {
+		want := l.Number == 10 || l.Number == 11
+		if l.Highlight != want {
+			t.Errorf("line %d highlight = %v, want %v", l.Number, l.Highlight, want)
+		}
+	}
+}
+
+func TestLoadSnippet_RelativeToCommandDir(t *testing.T) {
+	path := writeSourceFile(t, "a = 1\nb = 2\n")
+	m := &Model{
+		tab:     TabLog,
+		width:   80,
+		command: []string{"terraform", "-chdir=" + filepath.Dir(path), "plan"},
+		diagnostics: []Diagnostic{{
+			Severity: "error",
+			Summary:  "Invalid value",
+			Detail:   []DiagnosticLine{{Content: "  on main.tf line 2:", IsMarker: true}},
+		}},
+	}
+	m.rebuildLines()
+	m.toggleSource(0)
+	if snippet := m.snippets[0]; snippet.Err != "" || len(snippet.Lines) == 0 {
+		t.Errorf("expected the snippet read from the -chdir directory, got %+v", snippet)
+	}
+}
+
+func TestLoadSnippet_MissingFile(t *testing.T) {
+	snippet := loadSnippet(".", SourceLocation{File: filepath.Join(t.TempDir(), "gone.tf"), Line: 3}, nil)
+	if snippet.Err == "" {
+		t.Error("expected an error for a missing file")
+	}
+	if len(snippet.Lines) != 0 {
+		t.Errorf("expected no lines, got %d", len(snippet.Lines))
+	}
+}
+
+func TestLoadSnippet_ChangedFile(t *testing.T) {
+	path := writeSourceFile(t, "a = 1\nb = 2\n")
+
+	snippet := loadSnippet(".", SourceLocation{File: path, Line: 2}, map[int]string{2: "b = 3"})
+	if !snippet.Stale {
+		t.Error("expected snippet to be stale when the excerpt no longer matches")
+	}
+	if len(snippet.Lines) == 0 {
+		t.Error("stale snippet should still show the current file content")
+	}
+
+	snippet = loadSnippet(".", SourceLocation{File: path, Line: 40}, nil)
+	if !snippet.Stale || snippet.Err == "" {
+		t.Error("expected stale error when the line is beyond the end of the file")
+	}
+}
+
+func TestToggleSource(t *testing.T) {
+	path := writeSourceFile(t, "resource \"aws_instance\" \"web\" {\n  ami = \"bad\"\n}\n")
+
+	m := &Model{
+		tab:   TabLog,
+		width: 80,
+		diagnostics: []Diagnostic{
+			{
+				Severity: "error",
+				Summary:  "Invalid AMI",
+				Detail: []DiagnosticLine{
+					{Content: "  on " + path + " line 2, in resource \"aws_instance\" \"web\":", IsMarker: true},
+					{Content: "   2:   ami = \"bad\""},
+				},
+				Expanded: true,
+			},
+		},
+	}
+	m.rebuildLines()
+	before := len(m.lines)
+
+	m.toggleSource(0)
+	if !m.diagnostics[0].ShowSource {
+		t.Fatal("expected ShowSource to be set")
+	}
+
+	var sourceLines []Line
+	for _, l := range m.lines {
+		if l.Type == LineTypeSource {
+			sourceLines = append(sourceLines, l)
+		}
+	}
+	if len(sourceLines) != 4 { // heading + 3 file lines
+		t.Fatalf("expected 4 source lines, got %d", len(sourceLines))
+	}
+	if !strings.Contains(sourceLines[2].Content, "▶ 2 │") {
+		t.Errorf("expected referenced line to be marked, got %q", sourceLines[2].Content)
+	}
+	m.theme()
+	m.renderSourceLine(sourceLines[2], false)
+	if m.theme().ChangeAttr.GetBold() {
+		t.Error("expected highlighting a source line to leave the change style unbolded")
+	}
+
+	m.toggleSource(0)
+	if len(m.lines) != before {
+		t.Errorf("expected %d lines after hiding source, got %d", before, len(m.lines))
+	}
+}
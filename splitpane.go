@@ -0,0 +1,192 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	splitMinWidth     = 60    // Narrower terminals fall back to the inline layout
+	splitMinListWidth = 24    // Minimum width of the resource list
+	splitSeparator    = " │ " // Drawn between the two panes
+)
+
+// splitActive reports whether the Plan view is drawn as list + detail panes
+func (m Model) splitActive() bool {
+	return m.splitPane && m.tab == TabPlan && m.width >= splitMinWidth
+}
+
+// splitWidths returns the widths of the resource list and the detail pane
+func (m Model) splitWidths() (int, int) {
+	list := m.width * 2 / 5
+	if list < splitMinListWidth {
+		list = splitMinListWidth
+	}
+	detail := m.width - list - len([]rune(splitSeparator))
+	if detail < 1 {
+		detail = 1
+	}
+	return list, detail
+}
+
+// selectedResource returns the index of the resource under the cursor, or -1
+func (m Model) selectedResource() int {
+	if m.cursor < 0 || m.cursor >= len(m.lines) {
+		return -1
+	}
+	return m.lines[m.cursor].ResourceIdx
+}
+
+// toggleSplitPane switches between the inline and two-pane layouts, keeping
+// the selected resource under the cursor.
+func (m *Model) toggleSplitPane() {
+	selected := m.selectedResource()
+	m.splitPane = !m.splitPane
+	m.focusDetail = false
+	m.detailOffset = 0
+	m.rebuildLines()
+	for i, line := range m.lines {
+		if line.Type == LineTypeResource && line.ResourceIdx == selected {
+			m.cursor = i
+			break
+		}
+	}
+	m.clampCursor()
+	m.ensureCursorVisible()
+}
+
+// toggleSplitFocus moves keyboard focus between the list and detail panes
+func (m *Model) toggleSplitFocus() {
+	if m.splitActive() {
+		m.focusDetail = !m.focusDetail
+	}
+}
+
+// detailLines wraps the selected resource's attributes to the detail pane width
+func (m Model) detailLines() []Line {
+	resIdx := m.selectedResource()
+	if resIdx < 0 || resIdx >= len(m.resources) {
+		return nil
+	}
+	_, width := m.splitWidths()
+	if m.diffSideBySide(m.resources[resIdx]) {
+		return m.diffLines(resIdx, width)
+	}
+
+	var lines []Line
+	for j, attr := range m.resources[resIdx].Attributes {
+		for _, w := range wrapText(attr, width, getIndentForLine(attr)) {
+			lines = append(lines, Line{
+				Type:        LineTypeAttribute,
+				ResourceIdx: resIdx,
+				DiagIdx:     -1,
+				AttrIdx:     j,
+				Content:     w,
+			})
+		}
+	}
+	return lines
+}
+
+// detailHeight returns the number of attribute rows in the detail pane,
+// which gives its first row to the resource title.
+func (m *Model) detailHeight() int {
+	return m.visibleHeight() - 1
+}
+
+// detailScroll returns the detail pane offset for the selected resource;
+// moving to another resource starts its detail at the top.
+func (m Model) detailScroll() int {
+	if m.detailFor != m.selectedResource() {
+		return 0
+	}
+	return m.detailOffset
+}
+
+// scrollDetail scrolls the detail pane by delta lines
+func (m *Model) scrollDetail(delta int) {
+	m.detailOffset = m.detailScroll() + delta
+	m.detailFor = m.selectedResource()
+	maxOffset := len(m.detailLines()) - m.detailHeight()
+	if m.detailOffset > maxOffset {
+		m.detailOffset = maxOffset
+	}
+	if m.detailOffset < 0 {
+		m.detailOffset = 0
+	}
+}
+
+// handleDetailKey scrolls the detail pane when it has focus. It reports
+// whether the key was used.
+func (m *Model) handleDetailKey(key string) bool {
+	if !m.splitActive() || !m.focusDetail {
+		return false
+	}
+	switch key {
+	case "up", "k":
+		m.scrollDetail(-1)
+	case "down", "j":
+		m.scrollDetail(1)
+	case "pgup", "ctrl+u":
+		m.scrollDetail(-m.detailHeight() / 2)
+	case "pgdown", "ctrl+d":
+		m.scrollDetail(m.detailHeight() / 2)
+	case "home", "g":
+		m.scrollDetail(-len(m.detailLines()))
+	case "end", "G":
+		m.scrollDetail(len(m.detailLines()))
+	default:
+		return false
+	}
+	return true
+}
+
+// fitWidth truncates or pads a rendered line to exactly width cells
+func fitWidth(s string, width int) string {
+	s = lipgloss.NewStyle().MaxWidth(width).Render(s)
+	if pad := width - lipgloss.Width(s); pad > 0 {
+		s += strings.Repeat(" ", pad)
+	}
+	return s
+}
+
+// renderSplitPane renders rows of the resource list (lines start to end)
+// beside the detail pane for the selected resource.
+func (m Model) renderSplitPane(start, end, rows int, render func(int) string) string {
+	t := m.theme()
+	listWidth, detailWidth := m.splitWidths()
+
+	detail := m.detailLines()
+	offset := m.detailScroll()
+	title := "No resource selected"
+	if resIdx := m.selectedResource(); resIdx >= 0 && resIdx < len(m.resources) {
+		rc := m.resources[resIdx]
+		title = getSymbol(rc.Action) + " " + rc.Address + " " + rc.ActionText
+	}
+	titleStyle := t.Dim
+	if m.focusDetail {
+		titleStyle = t.Prompt
+	}
+
+	var b strings.Builder
+	for r := 0; r < rows; r++ {
+		left := ""
+		if i := start + r; i < end {
+			left = render(i)
+		}
+		right := ""
+		if r == 0 {
+			right = titleStyle.Render(title)
+		} else if i := offset + r - 1; i < len(detail) && detail[i].Type == LineTypeDiff {
+			right = m.renderDiffLine(detail[i], false, detailWidth)
+		} else if i < len(detail) {
+			right = m.renderAttributeLine(detail[i], false)
+		}
+		b.WriteString(fitWidth(left, listWidth))
+		b.WriteString(t.Dim.Render(splitSeparator))
+		b.WriteString(fitWidth(right, detailWidth))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
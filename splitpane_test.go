@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// splitResources has one resource too long to fit the detail pane and one
+// with a line too wide for it
+var splitResources = []ResourceChange{
+	planResource("aws_vpc.main", "create", tagAttributes(30)...),
+	planResource("aws_instance.web", "update", "~ user_data = \""+strings.Repeat("x", 120)+"\" -> (known after apply)"),
+}
+
+func tagAttributes(n int) []string {
+	var attrs []string
+	for i := 0; i < n; i++ {
+		attrs = append(attrs, fmt.Sprintf("+ tag_%02d = \"value\"", i))
+	}
+	return attrs
+}
+
+func TestSplitPaneListsOnlyResources(t *testing.T) {
+	m := planModel(100, 16, splitResources...)
+	m.resources[0].Expanded = true
+	m = typeKeys(m, "v")
+	if !m.splitActive() {
+		t.Fatal("expected v to enable the split layout")
+	}
+	if len(m.lines) != 2 {
+		t.Errorf("expected the list pane to hold only resource headers, got %d lines", len(m.lines))
+	}
+
+	view := stripANSI(m.View())
+	if !strings.Contains(view, "+ aws_vpc.main will be created") {
+		t.Errorf("expected detail title for the selected resource, got:\n%s", view)
+	}
+	if !strings.Contains(view, "tag_00") {
+		t.Errorf("expected detail pane to show attributes, got:\n%s", view)
+	}
+
+	m = typeKeys(m, "v")
+	if m.splitActive() || len(m.lines) != 32 {
+		t.Errorf("expected v to restore the inline layout, got %d lines", len(m.lines))
+	}
+}
+
+func TestSplitPaneRowsFitWidth(t *testing.T) {
+	m := planModel(100, 16, splitResources...)
+	m = typeKeys(m, "v", "j")
+	for _, row := range strings.Split(m.renderSplitPane(0, len(m.lines), 8, m.renderLine), "\n") {
+		if row == "" {
+			continue
+		}
+		if w := lipgloss.Width(row); w != m.width {
+			t.Errorf("row width %d, want %d: %q", w, m.width, stripANSI(row))
+		}
+	}
+
+	// Long attributes wrap to the detail pane's width
+	_, detailWidth := m.splitWidths()
+	detail := m.detailLines()
+	if len(detail) < 2 {
+		t.Fatalf("expected long attribute to wrap, got %d lines", len(detail))
+	}
+	for _, l := range detail {
+		if w := lipgloss.Width(l.Content); w > detailWidth {
+			t.Errorf("detail line wider than pane (%d > %d): %q", w, detailWidth, l.Content)
+		}
+	}
+}
+
+func TestSplitPaneFocusScrollsDetail(t *testing.T) {
+	m := planModel(100, 16, splitResources...)
+	m = typeKeys(m, "v", "tab", "j", "j", "j")
+	if !m.focusDetail {
+		t.Fatal("expected Tab to focus the detail pane")
+	}
+	if m.cursor != 0 {
+		t.Errorf("expected list cursor to stay put while detail has focus, got %d", m.cursor)
+	}
+	if got := m.detailScroll(); got != 3 {
+		t.Errorf("detail offset = %d, want 3", got)
+	}
+
+	m = typeKeys(m, "G")
+	if want := len(m.detailLines()) - m.detailHeight(); m.detailScroll() != want {
+		t.Errorf("G scrolled detail to %d, want %d", m.detailScroll(), want)
+	}
+
+	// Back in the list, moving to another resource starts its detail at the top
+	m = typeKeys(m, "tab", "j")
+	if m.focusDetail || m.cursor != 1 {
+		t.Fatalf("expected Tab to return focus to the list, focusDetail=%v cursor=%d", m.focusDetail, m.cursor)
+	}
+	if m.detailScroll() != 0 {
+		t.Errorf("expected detail offset reset for a new resource, got %d", m.detailScroll())
+	}
+}
+
+func TestSplitPaneFallsBackWhenNarrow(t *testing.T) {
+	m := planModel(100, 16, splitResources...)
+	m.resources[0].Expanded = true
+	m = typeKeys(m, "v")
+
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 40, Height: 16})
+	m = updated.(Model)
+	m.rebuildLines()
+	if m.splitActive() {
+		t.Error("expected narrow terminals to use the inline layout")
+	}
+	if len(m.lines) <= 2 {
+		t.Error("expected attributes inline after falling back")
+	}
+
+	updated, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 16})
+	m = updated.(Model)
+	m.rebuildLines()
+	if !m.splitActive() {
+		t.Error("expected the split layout to return when the terminal widens")
+	}
+	if list, detail := m.splitWidths(); list+detail+3 != 120 {
+		t.Errorf("pane widths %d+%d do not fill the terminal", list, detail)
+	}
+}
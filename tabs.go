@@ -0,0 +1,253 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Tab identifies one of the views in the tab bar
+type Tab int
+
+const (
+	TabPlan Tab = iota
+	TabDiagnostics
+	TabLog
+	TabOutputs
+	TabSummary
+	tabCount
+)
+
+// tabNames are the tab bar labels, indexed by Tab
+var tabNames = [tabCount]string{"Plan", "Diagnostics", "Log", "Outputs", "Summary"}
+
+// outputNamePattern matches the first line of an applied output ("name = value")
+var outputNamePattern = regexp.MustCompile(`^[\w-]+\s*=`)
+
+// diagViewState is the per-tab display state of a diagnostic
+type diagViewState struct {
+	expanded   bool
+	showSource bool
+	showHint   bool
+}
+
+// tabState is what a tab remembers while another tab is shown
+type tabState struct {
+	cursor int
+	offset int
+	diags  []diagViewState
+}
+
+// String returns the tab's label
+func (t Tab) String() string {
+	if t < 0 || t >= tabCount {
+		return ""
+	}
+	return tabNames[t]
+}
+
+// autoSwitchTab switches to a tab for newly arrived content, unless the view
+// was chosen on the command line
+func (m *Model) autoSwitchTab(tab Tab) {
+	if !m.fixedView {
+		m.switchTab(tab)
+	}
+}
+
+// switchTab saves the current tab's cursor, scroll position and diagnostic
+// expand state, then restores those of the target tab. Diagnostics a tab has
+// not seen yet keep their current state.
+func (m *Model) switchTab(tab Tab) {
+	if tab == m.tab || tab < 0 || tab >= tabCount {
+		return
+	}
+
+	saved := tabState{cursor: m.cursor, offset: m.offset}
+	for _, d := range m.diagnostics {
+		saved.diags = append(saved.diags, diagViewState{d.Expanded, d.ShowSource, d.ShowHint})
+	}
+	m.tabStates[m.tab] = saved
+
+	m.tab = tab
+	restore := m.tabStates[tab]
+	for i, s := range restore.diags {
+		if i < len(m.diagnostics) {
+			m.diagnostics[i].Expanded = s.expanded
+			m.diagnostics[i].ShowSource = s.showSource
+			m.diagnostics[i].ShowHint = s.showHint
+		}
+	}
+	m.rebuildLines()
+	m.cursor = restore.cursor
+	m.offset = restore.offset
+	m.clampCursor()
+	m.clampOffset()
+}
+
+// tabAt returns the tab under column x of the tab bar
+func (m Model) tabAt(x int) (Tab, bool) {
+	pos := 0
+	for i := Tab(0); i < tabCount; i++ {
+		w := lipgloss.Width(m.tabLabel(i))
+		if x >= pos && x < pos+w {
+			return i, true
+		}
+		pos += w
+	}
+	return 0, false
+}
+
+// tabLabel returns the plain text of a tab bar entry
+func (m Model) tabLabel(tab Tab) string {
+	label := fmt.Sprintf(" %d %s ", tab+1, tab)
+	if tab == TabDiagnostics && len(m.diagnostics) > 0 {
+		label = fmt.Sprintf(" %d %s (%d) ", tab+1, tab, len(m.diagnostics))
+	}
+	return label
+}
+
+// renderTabBar renders the tab bar with the active tab highlighted
+func (m Model) renderTabBar() string {
+	t := m.theme()
+	var b strings.Builder
+	for i := Tab(0); i < tabCount; i++ {
+		label := m.tabLabel(i)
+		switch {
+		case i != m.tab:
+			b.WriteString(t.Dim.Render(label))
+		case i == TabLog && m.hasError:
+			b.WriteString(t.HeaderError.Copy().Padding(0).Render(label))
+		case i == TabLog:
+			b.WriteString(t.HeaderLog.Copy().Padding(0).Render(label))
+		default:
+			b.WriteString(t.HeaderPlan.Copy().Padding(0).Render(label))
+		}
+	}
+	return b.String()
+}
+
+// parseOutputs collects output lines from the logs: planned changes from the
+// "Changes to Outputs:" section and values from the final "Outputs:" block
+// printed after an apply.
+func parseOutputs(logs []string) ([]string, []string) {
+	var planned, applied []string
+	section := ""
+	for _, line := range logs {
+		clean := stripANSI(line)
+		switch strings.TrimSpace(clean) {
+		case "Changes to Outputs:":
+			section = "planned"
+			continue
+		case "Outputs:":
+			section = "applied"
+			applied = nil
+			continue
+		}
+
+		indented := strings.HasPrefix(clean, " ")
+		switch section {
+		case "planned":
+			if indented {
+				planned = append(planned, clean)
+				continue
+			}
+		case "applied":
+			trimmed := strings.TrimSpace(clean)
+			if indented || outputNamePattern.MatchString(clean) || trimmed == "}" || trimmed == "]" {
+				applied = append(applied, clean)
+				continue
+			}
+		}
+		section = ""
+	}
+	return planned, applied
+}
+
+// appendTextLines wraps lines of plain text into the display as log lines
+func (m *Model) appendTextLines(lines ...string) {
+	for _, text := range lines {
+		for _, w := range wrapText(text, m.width-2, getIndentForLine(text)) {
+			m.lines = append(m.lines, Line{
+				Type:        LineTypeLog,
+				ResourceIdx: -1,
+				DiagIdx:     -1,
+				AttrIdx:     -1,
+				Content:     w,
+			})
+		}
+	}
+}
+
+// appendOutputLines builds the Outputs tab
+func (m *Model) appendOutputLines() {
+	planned, applied := parseOutputs(m.logs)
+	if len(planned) == 0 && len(applied) == 0 {
+		m.appendTextLines("No outputs")
+		return
+	}
+	if len(planned) > 0 {
+		m.appendTextLines("Changes to Outputs:")
+		m.appendTextLines(planned...)
+	}
+	if len(applied) > 0 {
+		if len(planned) > 0 {
+			m.appendTextLines(" ")
+		}
+		m.appendTextLines("Outputs:")
+		m.appendTextLines(applied...)
+	}
+}
+
+// appendSummaryLines builds the Summary tab
+func (m *Model) appendSummaryLines() {
+	if len(m.command) > 0 {
+		m.appendTextLines("Command:      " + strings.Join(m.command, " "))
+	}
+
+	status := "Running"
+	switch {
+	case m.hasError:
+		status = fmt.Sprintf("Failed (exit code %d)", m.exitCode)
+	case m.changesPresent():
+		status = fmt.Sprintf("Done, changes present (exit code %d)", m.exitCode)
+	case m.done:
+		status = "Done"
+	}
+	m.appendTextLines("Status:       " + status)
+
+	counts := make(map[string]int)
+	for _, rc := range m.resources {
+		counts[rc.Action]++
+	}
+	m.appendTextLines(fmt.Sprintf("Resources:    %d", len(m.resources)))
+	for _, action := range actionOrder {
+		if counts[action] > 0 {
+			m.appendTextLines(fmt.Sprintf("  %s %-9s %d", getSymbol(action), action, counts[action]))
+		}
+	}
+
+	var errors, warnings int
+	for _, d := range m.diagnostics {
+		if d.Severity == "error" {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+	m.appendTextLines(fmt.Sprintf("Diagnostics:  %d errors, %d warnings", errors, warnings))
+
+	planned, applied := parseOutputs(m.logs)
+	values := 0
+	for _, line := range applied {
+		if outputNamePattern.MatchString(line) {
+			values++
+		}
+	}
+	m.appendTextLines(fmt.Sprintf("Outputs:      %d planned changes, %d values", len(planned), values))
+
+	if len(m.crashes) > 0 {
+		m.appendTextLines(fmt.Sprintf("Crashes:      %d provider crash(es)", len(m.crashes)))
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tabsResources, tabsLogs and tabsDiagnostics give every tab something to show
+var tabsResources = []ResourceChange{
+	planResource("aws_vpc.main", "create", "+ id = (known after apply)"),
+	planResource("aws_subnet.a", "create", "+ id = (known after apply)"),
+	planResource("aws_subnet.b", "create", "+ id = (known after apply)"),
+	planResource("aws_instance.web", "create", "+ id = (known after apply)"),
+}
+
+var tabsLogs = []string{
+	"aws_vpc.main: Refreshing state...",
+	"Changes to Outputs:",
+	"  + vpc_id = (known after apply)",
+	"Plan: 4 to add, 0 to change, 0 to destroy.",
+}
+
+var tabsDiagnostics = []Diagnostic{
+	{Severity: "error", Summary: "Invalid reference", Detail: []DiagnosticLine{{Content: "detail"}}},
+	{Severity: "warning", Summary: "Deprecated attribute", Detail: []DiagnosticLine{{Content: "detail"}}},
+}
+
+func TestNumberKeysSwitchTabs(t *testing.T) {
+	m := planModel(120, 20, tabsResources...)
+	m.logs, m.diagnostics = tabsLogs, append([]Diagnostic(nil), tabsDiagnostics...)
+	m.rebuildLines()
+	for key, want := range map[string]Tab{"2": TabDiagnostics, "3": TabLog, "4": TabOutputs, "5": TabSummary, "1": TabPlan} {
+		m = typeKeys(m, key)
+		if m.tab != want {
+			t.Errorf("key %s: tab = %v, want %v", key, m.tab, want)
+		}
+	}
+}
+
+func TestDiagnosticsTabListsOnlyDiagnostics(t *testing.T) {
+	m := planModel(120, 20, tabsResources...)
+	m.logs, m.diagnostics = tabsLogs, append([]Diagnostic(nil), tabsDiagnostics...)
+	m.rebuildLines()
+	m = typeKeys(m, "2")
+	for _, l := range m.lines {
+		if l.Type == LineTypeLog || l.Type == LineTypeResource {
+			t.Fatalf("expected only diagnostics on the Diagnostics tab, got %+v", l)
+		}
+	}
+	view := stripANSI(m.View())
+	if !strings.Contains(view, "Invalid reference") || !strings.Contains(view, "Deprecated attribute") {
+		t.Errorf("expected errors and warnings listed, got:\n%s", view)
+	}
+	if !strings.Contains(view, "2 Diagnostics (2)") {
+		t.Errorf("expected the tab bar to count diagnostics, got:\n%s", view)
+	}
+}
+
+func TestTabsKeepCursorAndOffset(t *testing.T) {
+	m := planModel(120, 20, tabsResources...)
+	m.logs, m.diagnostics = tabsLogs, append([]Diagnostic(nil), tabsDiagnostics...)
+	m.rebuildLines()
+	m = typeKeys(m, "j", "j")
+	m = typeKeys(m, "3", "j")
+	if m.cursor != 1 {
+		t.Fatalf("expected the Log tab to start at its own cursor, got %d", m.cursor)
+	}
+	m = typeKeys(m, "1")
+	if m.cursor != 2 {
+		t.Errorf("expected Plan cursor restored to 2, got %d", m.cursor)
+	}
+	m = typeKeys(m, "3")
+	if m.cursor != 1 {
+		t.Errorf("expected Log cursor restored to 1, got %d", m.cursor)
+	}
+}
+
+func TestTabsKeepDiagnosticExpandState(t *testing.T) {
+	m := planModel(120, 20, tabsResources...)
+	m.logs, m.diagnostics = tabsLogs, append([]Diagnostic(nil), tabsDiagnostics...)
+	m.rebuildLines()
+	m = typeKeys(m, "3", "2", "e")
+	if !m.diagnostics[0].Expanded {
+		t.Fatal("expected e to expand diagnostics on the Diagnostics tab")
+	}
+	m = typeKeys(m, "3")
+	if m.diagnostics[0].Expanded {
+		t.Error("expected the Log tab to keep its own (collapsed) diagnostic state")
+	}
+	m = typeKeys(m, "2")
+	if !m.diagnostics[0].Expanded {
+		t.Error("expected the Diagnostics tab expand state restored")
+	}
+}
+
+func TestClickTabBar(t *testing.T) {
+	m := planModel(120, 20, tabsResources...)
+	m.logs, m.diagnostics = tabsLogs, append([]Diagnostic(nil), tabsDiagnostics...)
+	m.rebuildLines()
+	x := 0
+	for tab := TabPlan; tab < TabOutputs; tab++ {
+		x += len(m.tabLabel(tab))
+	}
+	updated, _ := m.Update(tea.MouseMsg{X: x + 1, Y: 0, Button: tea.MouseButtonLeft, Action: tea.MouseActionPress})
+	m = updated.(Model)
+	if m.tab != TabOutputs {
+		t.Errorf("expected click on the Outputs label to switch tabs, got %v", m.tab)
+	}
+	if !strings.HasPrefix(stripANSI(m.renderHeader()), " 1 Plan  2 Diagnostics (2)  3 Log  4 Outputs ") {
+		t.Errorf("expected the tab bar at the start of the header, got %q", stripANSI(m.renderHeader()))
+	}
+}
+
+func TestTabBarLeavesHeaderPadding(t *testing.T) {
+	m := planModel(120, 20, tabsResources...)
+	m.theme()
+	m.renderTabBar()
+	m.tab = TabLog
+	m.renderTabBar()
+	m.hasError = true
+	m.renderTabBar()
+	th := m.theme()
+	for name, style := range map[string]lipgloss.Style{"HeaderPlan": th.HeaderPlan, "HeaderLog": th.HeaderLog, "HeaderError": th.HeaderError} {
+		if style.GetPaddingLeft() != 1 || style.GetPaddingRight() != 1 {
+			t.Errorf("expected rendering the tab bar to leave %s padded", name)
+		}
+	}
+}
+
+func TestParseOutputs(t *testing.T) {
+	planned, applied := parseOutputs([]string{
+		"Changes to Outputs:",
+		"  + vpc_id = (known after apply)",
+		"  ~ subnets = [",
+		"      + \"subnet-1\",",
+		"    ]",
+		"Do you want to perform these actions?",
+		"Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",
+		"Outputs:",
+		"vpc_id = \"vpc-123\"",
+		"subnets = [",
+		"  \"subnet-1\",",
+		"]",
+		"some trailing log line",
+	})
+	if len(planned) != 4 {
+		t.Errorf("planned = %v", planned)
+	}
+	want := []string{`vpc_id = "vpc-123"`, "subnets = [", `  "subnet-1",`, "]"}
+	if !reflect.DeepEqual(applied, want) {
+		t.Errorf("applied = %v, want %v", applied, want)
+	}
+}
+
+func TestOutputsAndSummaryTabs(t *testing.T) {
+	m := planModel(120, 20, tabsResources...)
+	m.logs, m.diagnostics = tabsLogs, append([]Diagnostic(nil), tabsDiagnostics...)
+	m.rebuildLines()
+	m = typeKeys(m, "4")
+	if view := stripANSI(m.View()); !strings.Contains(view, "+ vpc_id = (known after apply)") {
+		t.Errorf("expected planned outputs on the Outputs tab, got:\n%s", view)
+	}
+
+	m = typeKeys(m, "5")
+	view := stripANSI(m.View())
+	for _, want := range []string{"Resources:    4", "+ create    4", "Diagnostics:  1 errors, 1 warnings", "Outputs:      1 planned changes"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected %q on the Summary tab, got:\n%s", want, view)
+		}
+	}
+}
+
+func TestFooterCountsDiagnosticsOnOtherTabs(t *testing.T) {
+	m := planModel(120, 20, tabsResources...)
+	m.logs, m.diagnostics = tabsLogs, append([]Diagnostic(nil), tabsDiagnostics...)
+	m.rebuildLines()
+	m = typeKeys(m, "4")
+	m.diagnostics = append(m.diagnostics, Diagnostic{Severity: "error", Summary: "Invalid reference", Detail: []DiagnosticLine{{Content: "detail"}}})
+	m.rebuildLines()
+	if footer := stripANSI(m.renderFooter()); !strings.Contains(footer, "2 unique / 3 total diagnostics") {
+		t.Errorf("expected the footer to count a diagnostic added on the Outputs tab, got %q", footer)
+	}
+}
@@ -4,13 +4,13 @@ import (
 	"testing"
 )
 
-func TestPlanViewTimingGap_ErrorSwitchesToLogView(t *testing.T) {
+func TestPlanViewTimingGap_ErrorSwitchesToDiagnosticsTab(t *testing.T) {
 	m := Model{
 		streamChan: make(chan StreamMsg, 10),
-		showLogs:   true, // Start in LOG view (default)
+		tab:        TabLog, // Start on the Log tab (default)
 	}
 
-	// 1. Receive a resource -> should switch to PLAN view
+	// 1. Receive a resource -> should switch to the Plan tab
 	res := ResourceChange{Address: "aws_instance.foo", Action: "create"}
 	msg1 := StreamMsg{Resource: &res}
 
@@ -20,11 +20,11 @@ func TestPlanViewTimingGap_ErrorSwitchesToLogView(t *testing.T) {
 	updatedModel, _ := m.Update(msg1)
 	m = updatedModel.(Model)
 
-	if m.showLogs {
-		t.Error("Expected to switch to PLAN view (showLogs=false) after receiving a resource")
+	if m.tab != TabPlan {
+		t.Error("Expected to switch to the Plan tab after receiving a resource")
 	}
 
-	// 2. Receive an ERROR diagnostic -> should switch back to LOG view IMMEDIATELY
+	// 2. Receive an ERROR diagnostic -> should switch to the Diagnostics tab IMMEDIATELY
 	// This covers the "timing gap" before exit code arrives.
 	diag := Diagnostic{Severity: "error", Summary: "Something went wrong"}
 	msg2 := StreamMsg{Diagnostic: &diag}
@@ -32,36 +32,36 @@ func TestPlanViewTimingGap_ErrorSwitchesToLogView(t *testing.T) {
 	updatedModel, _ = m.Update(msg2)
 	m = updatedModel.(Model)
 
-	if !m.showLogs {
-		t.Error("Expected to switch to LOG view (showLogs=true) immediately after receiving an error diagnostic")
+	if m.tab != TabDiagnostics {
+		t.Error("Expected to switch to the Diagnostics tab immediately after receiving an error diagnostic")
 	}
 
-	// 3. Receive ANOTHER resource AFTER error -> should STAY in LOG view
+	// 3. Receive ANOTHER resource AFTER error -> should STAY on the Diagnostics tab
 	res2 := ResourceChange{Address: "aws_s3_bucket.data", Action: "create"}
 	msg3 := StreamMsg{Resource: &res2}
 
 	updatedModel, _ = m.Update(msg3)
 	m = updatedModel.(Model)
 
-	if !m.showLogs {
-		t.Error("Expected to STAY in LOG view after receiving a resource when error diagnostics exist")
+	if m.tab != TabDiagnostics {
+		t.Error("Expected to STAY on the Diagnostics tab after receiving a resource when error diagnostics exist")
 	}
 }
 
-func TestPlanViewTimingGap_WarningDoesNotSwitchToLog(t *testing.T) {
+func TestPlanViewTimingGap_WarningStaysOnPlanTab(t *testing.T) {
 	m := Model{
 		streamChan: make(chan StreamMsg, 10),
-		showLogs:   false, // Start in PLAN view
+		tab:        TabPlan, // Start on the Plan tab
 	}
 
-	// Warning diagnostic should NOT auto-switch to LOG view
+	// Warning diagnostic should NOT auto-switch to the Diagnostics tab
 	diag := Diagnostic{Severity: "warning", Summary: "Deprecated resource"}
 	msg := StreamMsg{Diagnostic: &diag}
 
 	updatedModel, _ := m.Update(msg)
 	m = updatedModel.(Model)
 
-	if m.showLogs {
-		t.Error("Warning diagnostic should NOT auto-switch to LOG view")
+	if m.tab != TabPlan {
+		t.Error("Warning diagnostic should NOT auto-switch away from the Plan tab")
 	}
 }
@@ -0,0 +1,628 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+const (
+	// vtTabWidth is the distance between tab stops
+	vtTabWidth = 8
+	// vtMaxLineWidth bounds cursor moves and insertions on a screen without a
+	// width, so one escape sequence can't grow a row without limit
+	vtMaxLineWidth = 4096
+	// vtMaxParam clamps CSI parameters so arithmetic on them can't overflow
+	vtMaxParam = 1 << 16
+)
+
+// vtCell is one character cell of the emulated terminal
+type vtCell struct {
+	ch    string // Character drawn in the cell ("" when blank)
+	style string // SGR sequences in effect when the cell was written
+	cont  bool   // Right half of a wide character
+}
+
+// Parser states of vtScreen
+const (
+	vtGround = iota
+	vtEscape
+	vtCharset // ESC ( and friends, which take one more byte
+	vtCSI
+	vtOSC
+	vtOSCEscape
+)
+
+// vtScreen is a minimal VT100/xterm emulator: enough of the control sequences
+// used by prompts, progress bars and full-screen programs to reproduce what a
+// terminal would show. Colors and attributes are kept as raw SGR sequences.
+// Rows only hold the cells written so far; a width of 0 never wraps.
+type vtScreen struct {
+	width, height int
+	rows          [][]vtCell
+	dirty         []bool // Rows changed since the last clearDirty
+	x, y          int
+	style         string // SGR sequences applied to newly written cells
+	wrapPending   bool   // Cursor is past the last column; the next character wraps
+	top, bottom   int    // Scroll region rows, inclusive
+	savedX        int
+	savedY        int
+	savedStyle    string
+	hideCursor    bool
+	altRows       [][]vtCell // Main screen saved while the alternate screen is shown
+
+	newlineMode bool        // Line feed also returns the carriage, as for a log
+	onScroll    func(n int) // Called before the top n rows scroll off the screen
+
+	state  int
+	params []byte // CSI parameter and intermediate bytes
+	utf8   []byte // Incomplete UTF-8 sequence carried over between writes
+}
+
+// newVTScreen returns a blank screen of the given size
+func newVTScreen(width, height int) *vtScreen {
+	s := &vtScreen{}
+	s.resize(width, height)
+	return s
+}
+
+// resize changes the screen size, keeping the content at the top left. The
+// main screen saved behind the alternate screen and the saved cursor are
+// resized with it.
+func (s *vtScreen) resize(width, height int) {
+	width = max(width, 0)
+	height = max(height, 1)
+	// Keep the rows nearest the cursor when the screen gets shorter
+	shift := max(s.y-height+1, 0)
+	savedShift := shift
+	if s.altRows != nil {
+		// The saved cursor belongs to the main screen, which moves on its own
+		savedShift = max(s.savedY-height+1, 0)
+		s.altRows = fitRows(s.altRows, width, height, savedShift)
+	}
+	s.rows = fitRows(s.rows, width, height, shift)
+	s.dirty = make([]bool, height)
+	s.width, s.height = width, height
+	s.top, s.bottom = 0, height-1
+	s.moveTo(s.x, s.y-shift)
+
+	s.savedX, s.savedY = max(s.savedX, 0), clampInt(s.savedY-savedShift, 0, height-1)
+	if width > 0 {
+		s.savedX = min(s.savedX, width-1)
+	}
+}
+
+// fitRows returns height rows starting at rows[shift], cut to width
+func fitRows(rows [][]vtCell, width, height, shift int) [][]vtCell {
+	out := make([][]vtCell, height)
+	for i := range out {
+		if src := i + shift; src < len(rows) {
+			out[i] = rows[src]
+			if width > 0 && len(out[i]) > width {
+				out[i] = out[i][:width]
+			}
+		}
+	}
+	return out
+}
+
+// cell returns the cell at column x of row y, growing the row if needed
+func (s *vtScreen) cell(x, y int) *vtCell {
+	for len(s.rows[y]) <= x {
+		s.rows[y] = append(s.rows[y], vtCell{})
+	}
+	s.dirty[y] = true
+	return &s.rows[y][x]
+}
+
+// clearDirty forgets which rows have changed
+func (s *vtScreen) clearDirty() {
+	for i := range s.dirty {
+		s.dirty[i] = false
+	}
+}
+
+// Write feeds terminal output to the emulator
+func (s *vtScreen) Write(p []byte) (int, error) {
+	data := p
+	if len(s.utf8) > 0 {
+		data = append(s.utf8, p...)
+		s.utf8 = nil
+	}
+	for i := 0; i < len(data); {
+		b := data[i]
+		if s.state != vtGround || b < 0x80 {
+			s.step(b)
+			i++
+			continue
+		}
+		if !utf8.FullRune(data[i:]) {
+			s.utf8 = append([]byte(nil), data[i:]...)
+			break
+		}
+		r, size := utf8.DecodeRune(data[i:])
+		s.print(r)
+		i += size
+	}
+	return len(p), nil
+}
+
+// step advances the parser by one byte
+func (s *vtScreen) step(b byte) {
+	switch s.state {
+	case vtGround:
+		s.control(b)
+
+	case vtEscape:
+		s.state = vtGround
+		switch b {
+		case '[':
+			s.state = vtCSI
+			s.params = s.params[:0]
+		case ']':
+			s.state = vtOSC
+		case '(', ')', '*', '+', '#':
+			s.state = vtCharset
+		case '7':
+			s.saveCursor()
+		case '8':
+			s.restoreCursor()
+		case 'D':
+			s.lineFeed()
+		case 'E':
+			s.x = 0
+			s.lineFeed()
+		case 'M':
+			s.reverseIndex()
+		case 'c':
+			s.reset()
+		}
+
+	case vtCharset:
+		s.state = vtGround
+
+	case vtCSI:
+		switch {
+		case b >= 0x20 && b <= 0x3f:
+			s.params = append(s.params, b)
+		case b >= 0x40 && b <= 0x7e:
+			s.state = vtGround
+			s.csi(b, string(s.params))
+		case b == 0x1b:
+			s.state = vtEscape
+		default:
+			s.control(b)
+		}
+
+	case vtOSC:
+		switch b {
+		case 0x07:
+			s.state = vtGround
+		case 0x1b:
+			s.state = vtOSCEscape
+		}
+
+	case vtOSCEscape:
+		// ESC \ ends the string; anything else is treated the same way
+		s.state = vtGround
+	}
+}
+
+// control handles a C0 control character or printable ASCII
+func (s *vtScreen) control(b byte) {
+	switch b {
+	case 0x1b:
+		s.state = vtEscape
+	case '\r':
+		s.x = 0
+		s.wrapPending = false
+	case '\n', '\v', '\f':
+		if s.newlineMode {
+			s.x = 0
+		}
+		s.lineFeed()
+	case '\b':
+		if s.x > 0 {
+			s.x--
+		}
+		s.wrapPending = false
+	case '\t':
+		s.moveTo((s.x/vtTabWidth+1)*vtTabWidth, s.y)
+	default:
+		if b >= 0x20 && b < 0x7f {
+			s.print(rune(b))
+		}
+	}
+}
+
+// print writes a character at the cursor and advances it
+func (s *vtScreen) print(r rune) {
+	w := runewidth.RuneWidth(r)
+	if w == 0 {
+		// Combining characters join the previous cell
+		x := s.x - 1
+		if s.wrapPending {
+			x = s.x
+		}
+		if x >= 0 {
+			s.cell(x, s.y).ch += string(r)
+		}
+		return
+	}
+	if s.wrapPending || (w == 2 && s.width > 0 && s.x == s.width-1) {
+		s.x = 0
+		s.lineFeed()
+	}
+	*s.cell(s.x, s.y) = vtCell{ch: runeString(r), style: s.style}
+	if w == 2 && (s.width == 0 || s.x+1 < s.width) {
+		*s.cell(s.x+1, s.y) = vtCell{style: s.style, cont: true}
+	}
+	s.x += w
+	if s.width > 0 && s.x >= s.width {
+		s.x = s.width - 1
+		s.wrapPending = true
+	}
+}
+
+// lineFeed moves the cursor down, scrolling at the bottom of the region
+func (s *vtScreen) lineFeed() {
+	s.wrapPending = false
+	if s.y == s.bottom {
+		s.scrollUp(1)
+	} else if s.y < s.height-1 {
+		s.y++
+	}
+}
+
+// reverseIndex moves the cursor up, scrolling at the top of the region
+func (s *vtScreen) reverseIndex() {
+	s.wrapPending = false
+	if s.y == s.top {
+		s.scrollDown(1)
+	} else if s.y > 0 {
+		s.y--
+	}
+}
+
+// scrollUp moves the scroll region up n rows, adding blank rows at the bottom
+func (s *vtScreen) scrollUp(n int) {
+	if s.onScroll != nil && s.top == 0 && s.altRows == nil {
+		s.onScroll(min(n, s.bottom+1))
+	}
+	s.deleteRows(s.top, n)
+}
+
+// scrollDown moves the scroll region down n rows, adding blank rows at the top
+func (s *vtScreen) scrollDown(n int) {
+	s.insertRows(s.top, n)
+}
+
+// insertRows inserts n blank rows at row y, pushing rows below it down
+// within the scroll region
+func (s *vtScreen) insertRows(y, n int) {
+	if y < s.top || y > s.bottom {
+		return
+	}
+	n = min(n, s.bottom-y+1)
+	copy(s.rows[y+n:s.bottom+1], s.rows[y:s.bottom+1-n])
+	copy(s.dirty[y+n:s.bottom+1], s.dirty[y:s.bottom+1-n])
+	for i := y; i < y+n; i++ {
+		s.rows[i] = nil
+		s.dirty[i] = true
+	}
+}
+
+// deleteRows removes n rows at row y, pulling rows below it up within the
+// scroll region
+func (s *vtScreen) deleteRows(y, n int) {
+	if y < s.top || y > s.bottom {
+		return
+	}
+	n = min(n, s.bottom-y+1)
+	// Reuse the deleted rows' storage for the blank rows
+	deleted := append([][]vtCell(nil), s.rows[y:y+n]...)
+	copy(s.rows[y:], s.rows[y+n:s.bottom+1])
+	copy(s.dirty[y:], s.dirty[y+n:s.bottom+1])
+	for i := s.bottom - n + 1; i <= s.bottom; i++ {
+		s.rows[i] = deleted[i-(s.bottom-n+1)][:0]
+		s.dirty[i] = true
+	}
+}
+
+// eraseCells blanks columns [from, to) of row y. Erasing to the end of the
+// row (to < 0) drops the cells.
+func (s *vtScreen) eraseCells(y, from, to int) {
+	row := s.rows[y]
+	from = max(from, 0)
+	if to < 0 || to >= len(row) {
+		if from < len(row) {
+			s.rows[y] = row[:from]
+		}
+	} else {
+		for x := from; x < to; x++ {
+			row[x] = vtCell{}
+		}
+	}
+	s.dirty[y] = true
+}
+
+// reset returns the terminal to its initial state (ESC c)
+func (s *vtScreen) reset() {
+	width, height := s.width, s.height
+	*s = vtScreen{newlineMode: s.newlineMode, onScroll: s.onScroll}
+	s.resize(width, height)
+}
+
+// saveCursor remembers the cursor position and style (ESC 7, CSI s)
+func (s *vtScreen) saveCursor() {
+	s.savedX, s.savedY, s.savedStyle = s.x, s.y, s.style
+}
+
+// restoreCursor returns to the saved cursor position and style (ESC 8, CSI u)
+func (s *vtScreen) restoreCursor() {
+	s.x, s.y, s.style = s.savedX, s.savedY, s.savedStyle
+	s.wrapPending = false
+}
+
+// lineWidth returns the number of columns the cursor may move across
+func (s *vtScreen) lineWidth() int {
+	if s.width > 0 {
+		return s.width
+	}
+	return vtMaxLineWidth
+}
+
+// moveTo places the cursor, clamped to the screen
+func (s *vtScreen) moveTo(x, y int) {
+	s.x = clampInt(x, 0, s.lineWidth()-1)
+	s.y = clampInt(y, 0, s.height-1)
+	s.wrapPending = false
+}
+
+// csi executes a control sequence with the given final byte and parameters
+func (s *vtScreen) csi(final byte, params string) {
+	if strings.HasPrefix(params, "?") {
+		s.privateMode(final, params[1:])
+		return
+	}
+	if strings.IndexAny(params, "<=> !\"#$%&'()*+,-./") >= 0 {
+		return // Other private or intermediate forms are not supported
+	}
+	args := parseCSIParams(params)
+	arg := func(i, def int) int {
+		if i < len(args) && args[i] > 0 {
+			return args[i]
+		}
+		return def
+	}
+
+	switch final {
+	case 'A':
+		s.moveTo(s.x, s.y-arg(0, 1))
+	case 'B', 'e':
+		s.moveTo(s.x, s.y+arg(0, 1))
+	case 'C', 'a':
+		s.moveTo(s.x+arg(0, 1), s.y)
+	case 'D':
+		s.moveTo(s.x-arg(0, 1), s.y)
+	case 'E':
+		s.moveTo(0, s.y+arg(0, 1))
+	case 'F':
+		s.moveTo(0, s.y-arg(0, 1))
+	case 'G', '`':
+		s.moveTo(arg(0, 1)-1, s.y)
+	case 'd':
+		s.moveTo(s.x, arg(0, 1)-1)
+	case 'H', 'f':
+		s.moveTo(arg(1, 1)-1, arg(0, 1)-1)
+	case 'J':
+		switch arg(0, 0) {
+		case 0:
+			s.eraseCells(s.y, s.x, -1)
+			for y := s.y + 1; y < s.height; y++ {
+				s.eraseCells(y, 0, -1)
+			}
+		case 1:
+			s.eraseCells(s.y, 0, s.x+1)
+			for y := 0; y < s.y; y++ {
+				s.eraseCells(y, 0, -1)
+			}
+		case 2, 3:
+			for y := 0; y < s.height; y++ {
+				s.eraseCells(y, 0, -1)
+			}
+		}
+	case 'K':
+		switch arg(0, 0) {
+		case 0:
+			s.eraseCells(s.y, s.x, -1)
+		case 1:
+			s.eraseCells(s.y, 0, s.x+1)
+		case 2:
+			s.eraseCells(s.y, 0, -1)
+		}
+	case 'L':
+		s.insertRows(s.y, arg(0, 1))
+	case 'M':
+		s.deleteRows(s.y, arg(0, 1))
+	case 'P':
+		if row := s.rows[s.y]; s.x < len(row) {
+			n := min(arg(0, 1), len(row)-s.x)
+			s.rows[s.y] = append(row[:s.x], row[s.x+n:]...)
+			s.dirty[s.y] = true
+		}
+	case '@':
+		if row := s.rows[s.y]; s.x < len(row) {
+			// Cells pushed past the last column are lost
+			limit := max(s.lineWidth(), len(row))
+			blanks := make([]vtCell, clampInt(arg(0, 1), 0, limit-s.x))
+			row = append(row[:s.x], append(blanks, row[s.x:]...)...)
+			row = row[:min(len(row), limit)]
+			s.rows[s.y] = row
+			s.dirty[s.y] = true
+		}
+	case 'X':
+		s.eraseCells(s.y, s.x, s.x+arg(0, 1))
+	case 'S':
+		s.scrollUp(arg(0, 1))
+	case 'T':
+		s.scrollDown(arg(0, 1))
+	case 'r':
+		top, bottom := arg(0, 1)-1, arg(1, s.height)-1
+		if top < bottom && bottom < s.height {
+			s.top, s.bottom = top, bottom
+			s.moveTo(0, 0)
+		}
+	case 's':
+		s.saveCursor()
+	case 'u':
+		s.restoreCursor()
+	case 'm':
+		s.sgr(params)
+	}
+}
+
+// privateMode handles DEC private modes (CSI ? Pn h/l)
+func (s *vtScreen) privateMode(final byte, params string) {
+	if final != 'h' && final != 'l' {
+		return
+	}
+	set := final == 'h'
+	for _, mode := range parseCSIParams(params) {
+		switch mode {
+		case 25:
+			s.hideCursor = !set
+		case 47, 1047, 1049:
+			s.setAltScreen(set)
+		}
+	}
+}
+
+// setAltScreen switches to a blank alternate screen, or back to the saved
+// main screen
+func (s *vtScreen) setAltScreen(on bool) {
+	if on == (s.altRows != nil) {
+		return
+	}
+	if on {
+		s.saveCursor()
+		s.altRows = s.rows
+		s.rows = make([][]vtCell, s.height)
+	} else {
+		s.rows = s.altRows
+		s.altRows = nil
+		s.restoreCursor()
+	}
+	for i := range s.dirty {
+		s.dirty[i] = true
+	}
+}
+
+// sgr records a Select Graphic Rendition sequence. A reset clears the
+// recorded sequences; anything else is appended to them.
+func (s *vtScreen) sgr(params string) {
+	switch {
+	case params == "" || params == "0":
+		s.style = ""
+	case strings.HasPrefix(params, "0;"):
+		s.style = "\x1b[" + params + "m"
+	default:
+		s.style += "\x1b[" + params + "m"
+	}
+}
+
+// parseCSIParams parses semicolon-separated numeric parameters. Missing
+// parameters are 0.
+func parseCSIParams(params string) []int {
+	if params == "" {
+		return nil
+	}
+	parts := strings.Split(params, ";")
+	args := make([]int, len(parts))
+	for i, p := range parts {
+		// Sub-parameters (e.g. "4:3") only use their first value
+		p, _, _ = strings.Cut(p, ":")
+		n, _ := strconv.Atoi(p)
+		args[i] = min(n, vtMaxParam)
+	}
+	return args
+}
+
+// renderRow returns row y with its SGR sequences. Trailing blank cells are
+// dropped. If cursor is true the cell under the cursor is shown reversed.
+func (s *vtScreen) renderRow(y int, cursor bool) string {
+	row := s.rows[y]
+	end := len(row)
+	for end > 0 && row[end-1].ch == "" && !row[end-1].cont && row[end-1].style == "" {
+		end--
+	}
+	cursorX := -1
+	if cursor && !s.hideCursor && y == s.y {
+		cursorX = s.x
+		end = max(end, s.x+1)
+	}
+
+	var b strings.Builder
+	style := ""
+	for x := 0; x < end; x++ {
+		var c vtCell
+		if x < len(row) {
+			c = row[x]
+		}
+		if c.cont {
+			continue
+		}
+		if c.style != style {
+			switch {
+			case style == "":
+				b.WriteString(c.style)
+			case c.style != "" && strings.HasPrefix(c.style, style):
+				b.WriteString(c.style[len(style):])
+			default:
+				b.WriteString("\x1b[0m" + c.style)
+			}
+			style = c.style
+		}
+		ch := c.ch
+		if ch == "" {
+			ch = " "
+		}
+		if x == cursorX {
+			ch = "\x1b[7m" + ch + "\x1b[27m"
+		}
+		b.WriteString(ch)
+	}
+	if style != "" {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// text returns row y without styling, trailing blanks removed
+func (s *vtScreen) text(y int) string {
+	return stripANSI(s.renderRow(y, false))
+}
+
+// clampInt limits v to [lo, hi]
+func clampInt(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
+
+// asciiStrings caches the strings of printable ASCII characters, which make
+// up most terminal output
+var asciiStrings = func() (t [0x80]string) {
+	for r := range t {
+		t[r] = string(rune(r))
+	}
+	return
+}()
+
+// runeString returns r as a string without allocating for ASCII
+func runeString(r rune) string {
+	if r >= 0 && r < 0x80 {
+		return asciiStrings[r]
+	}
+	return string(r)
+}
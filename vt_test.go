@@ -0,0 +1,133 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func screenText(s *vtScreen) []string {
+	var rows []string
+	for y := 0; y < s.height; y++ {
+		rows = append(rows, s.text(y))
+	}
+	for len(rows) > 0 && rows[len(rows)-1] == "" {
+		rows = rows[:len(rows)-1]
+	}
+	return rows
+}
+
+func TestVTCarriageReturnAndErase(t *testing.T) {
+	s := newVTScreen(40, 5)
+	s.Write([]byte("Downloading 10%\rDownloading 100%\r\nDone\x1b[K"))
+	s.Write([]byte("\rAll done\x1b[K"))
+	got := screenText(s)
+	want := []string{"Downloading 100%", "All done"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestVTCursorMovement(t *testing.T) {
+	s := newVTScreen(20, 5)
+	s.Write([]byte("one\r\ntwo\r\nthree\r\n"))
+	s.Write([]byte("\x1b[2A\x1b[2KTWO\x1b[2B"))
+	s.Write([]byte("\x1b[1;1Hx\x1b[5;10Hend"))
+	got := screenText(s)
+	want := []string{"xne", "TWO", "three", "", "         end"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestVTWrapAndScroll(t *testing.T) {
+	s := newVTScreen(5, 2)
+	s.Write([]byte("abcdefg\r\nhi"))
+	got := screenText(s)
+	want := []string{"fg", "hi"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestVTSplitUTF8AndWideRunes(t *testing.T) {
+	s := newVTScreen(10, 2)
+	b := []byte("a€日b")
+	s.Write(b[:2])
+	s.Write(b[2:])
+	if got := s.text(0); got != "a€日b" {
+		t.Errorf("got %q", got)
+	}
+	if s.x != 5 {
+		t.Errorf("expected the wide rune to take two columns, cursor at %d", s.x)
+	}
+}
+
+func TestVTStylesAndIgnoredSequences(t *testing.T) {
+	s := newVTScreen(20, 2)
+	s.Write([]byte("\x1b]0;title\x07\x1b[?25l\x1b[1mbold\x1b[32m green\x1b[0m plain"))
+	if got := s.renderRow(0, false); got != "\x1b[1mbold\x1b[32m green\x1b[0m plain" {
+		t.Errorf("unexpected styled row %q", got)
+	}
+	if !s.hideCursor {
+		t.Error("expected ?25l to hide the cursor")
+	}
+}
+
+func TestVTAltScreen(t *testing.T) {
+	s := newVTScreen(10, 3)
+	s.Write([]byte("shell$ "))
+	s.Write([]byte("\x1b[?1049h\x1b[Hfull screen"))
+	if got := s.text(0); got != "full scree" {
+		t.Errorf("expected the alternate screen, got %q", got)
+	}
+	s.Write([]byte("\x1b[?1049l"))
+	if got := s.text(0); got != "shell$ " || s.x != 7 {
+		t.Errorf("expected the main screen and cursor restored, got %q at %d", got, s.x)
+	}
+}
+
+func TestVTRestoreCursorAfterShrink(t *testing.T) {
+	s := newVTScreen(80, 40)
+	s.Write([]byte("\x1b[35;60Hsaved\x1b[35;60H\x1b7"))
+	s.resize(20, 10)
+	s.Write([]byte("\x1b8x"))
+	if s.y != 9 || s.x > 19 {
+		t.Errorf("expected the saved cursor moved with its row into the screen, got (%d, %d)", s.x, s.y)
+	}
+	if got := s.text(9); !strings.HasSuffix(got, "x") {
+		t.Errorf("expected the print at the restored cursor, got %q", got)
+	}
+}
+
+func TestVTLeaveAltScreenAfterResize(t *testing.T) {
+	s := newVTScreen(80, 24)
+	s.Write([]byte("\x1b[24;1Hshell$ "))
+	s.Write([]byte("\x1b[?1049h\x1b[Hfull screen"))
+	s.resize(40, 10)
+	s.Write([]byte("\x1b[?1049l"))
+	s.Write([]byte("ls"))
+	if got := s.text(9); got != "shell$ ls" {
+		t.Errorf("expected the main screen resized behind the alternate one, got %q", got)
+	}
+	for y := 0; y < s.height; y++ {
+		s.renderRow(y, true)
+	}
+}
+
+func TestVTOversizedEscapeArguments(t *testing.T) {
+	s := newVTScreen(0, 3)
+	s.Write([]byte("\x1b[5000000Cx"))
+	if n := len(s.rows[0]); n > vtMaxLineWidth {
+		t.Errorf("expected a cursor move to stay within %d columns, row grew to %d", vtMaxLineWidth, n)
+	}
+
+	s.Write([]byte("\r\x1b[5000000@"))
+	if n := len(s.rows[0]); n > vtMaxLineWidth {
+		t.Errorf("expected an insert to stay within %d columns, row grew to %d", vtMaxLineWidth, n)
+	}
+
+	s.Write([]byte("\r\n\x1b[99999999999999999999999Cy"))
+	if s.x != vtMaxLineWidth {
+		t.Errorf("expected an overflowing move to reach the last column, cursor at %d", s.x)
+	}
+}